@@ -0,0 +1,148 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Aggregator pre-aggregates latency and error stats per route in memory and sends one summarized
+// transaction (op "http.server.aggregate") per route per interval instead of one per request,
+// to save quota of high-QPS services. Error events are still captured as configured.
+type Aggregator struct {
+	interval time.Duration
+
+	mu     sync.Mutex
+	start  time.Time
+	timer  *time.Timer
+	routes map[routeKey]*routeStats
+	// client and clock of the middleware that recorded the last request
+	client *sentry.Client
+	clock  func() time.Time
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+type routeStats struct {
+	count  int
+	errors int
+	total  time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// NewAggregator returns an aggregator sending summaries every interval
+func NewAggregator(interval time.Duration) *Aggregator {
+	return &Aggregator{
+		interval: interval,
+		routes:   make(map[routeKey]*routeStats),
+	}
+}
+
+// Flush sends the summaries of the current interval immediately, call it before the application exits
+func (a *Aggregator) Flush() {
+	a.mu.Lock()
+	routes, start, client, clock := a.routes, a.start, a.client, a.clock
+	a.routes = make(map[routeKey]*routeStats)
+
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	a.mu.Unlock()
+
+	if len(routes) == 0 {
+		return
+	}
+
+	end := clock()
+
+	for key, stats := range routes {
+		sendAggregate(client, key, stats, start, end)
+	}
+}
+
+func (a *Aggregator) add(client *sentry.Client, clock func() time.Time, method, route string, duration time.Duration, failed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.client, a.clock = client, clock
+
+	if a.timer == nil {
+		a.start = clock()
+		a.timer = time.AfterFunc(a.interval, a.Flush)
+	}
+
+	key := routeKey{method: method, route: route}
+
+	stats, ok := a.routes[key]
+	if !ok {
+		stats = &routeStats{min: duration}
+		a.routes[key] = stats
+	}
+
+	stats.count++
+	stats.total += duration
+	stats.min = min(stats.min, duration)
+	stats.max = max(stats.max, duration)
+
+	if failed {
+		stats.errors++
+	}
+}
+
+func sendAggregate(client *sentry.Client, key routeKey, stats *routeStats, start, end time.Time) {
+	hub := sentry.CurrentHub().Clone()
+	if client != nil {
+		hub.BindClient(client)
+	}
+
+	span := sentry.StartTransaction(sentry.SetHubOnContext(context.Background(), hub), key.method+" "+key.route,
+		sentry.WithOpName("http.server.aggregate"),
+		sentry.WithTransactionSource(sentry.SourceRoute),
+		sentry.WithSpanSampled(sentry.SampledTrue),
+	)
+
+	span.StartTime = start
+	span.SetTag("aggregated", "true")
+	span.SetTag("http.method", key.method)
+	span.SetTag("path", key.route)
+	span.SetData("aggregate.count", stats.count)
+	span.SetData("aggregate.errors", stats.errors)
+	span.SetData("aggregate.duration_avg_ms", (stats.total / time.Duration(stats.count)).Milliseconds())
+	span.SetData("aggregate.duration_min_ms", stats.min.Milliseconds())
+	span.SetData("aggregate.duration_max_ms", stats.max.Milliseconds())
+
+	if stats.errors > 0 {
+		span.Status = sentry.SpanStatusInternalError
+	} else {
+		span.Status = sentry.SpanStatusOK
+	}
+
+	span.EndTime = end
+	span.Finish()
+}
+
+// aggregateRequest records the request in the aggregator and drops its own transaction
+func aggregateRequest(config SentryConfig, span *sentry.Span, method, route string, status int, err error) {
+	// nested registrations add child spans, the request is aggregated by the transaction
+	if config.Aggregator == nil || !span.IsTransaction() {
+		return
+	}
+
+	var client *sentry.Client
+	if hub := sentry.GetHubFromContext(span.Context()); hub != nil {
+		client = hub.Client()
+	}
+
+	config.Aggregator.add(client, config.Clock, method, route, config.Clock().Sub(span.StartTime),
+		err != nil || status >= http.StatusInternalServerError)
+
+	span.Sampled = sentry.SampledFalse
+}
@@ -0,0 +1,93 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestAggregator() {
+	aggregator := NewAggregator(time.Hour)
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{Aggregator: aggregator}))
+	s.e.GET("/users/:id", func(c echo.Context) error {
+		if c.Param("id") == "0" {
+			return errors.New("test error")
+		}
+
+		return c.NoContent(http.StatusOK)
+	})
+	s.e.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/0", "/health"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	s.Empty(s.transport.Events())
+
+	aggregator.Flush()
+
+	events := s.transport.Events()
+	s.Require().Len(events, 2)
+
+	summaries := make(map[string]*sentry.Event)
+	for _, event := range events {
+		s.Equal("transaction", event.Type)
+		s.Equal("true", event.Tags["aggregated"])
+		summaries[event.Transaction] = event
+	}
+
+	users := summaries["GET /users/:id"]
+	s.Require().NotNil(users)
+	s.Equal(3, users.Extra["aggregate.count"])
+	s.Equal(1, users.Extra["aggregate.errors"])
+	s.Equal(sentry.SpanStatusInternalError, users.Contexts["trace"]["status"])
+
+	health := summaries["GET /health"]
+	s.Require().NotNil(health)
+	s.Equal(1, health.Extra["aggregate.count"])
+	s.Equal(0, health.Extra["aggregate.errors"])
+
+	aggregator.Flush()
+	s.Len(s.transport.Events(), 2)
+}
+
+func (s *MiddlewareTestSuite) TestAggregatorUsesRequestClientAndClock() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	aggregator := NewAggregator(time.Hour)
+
+	transport := &TransportMock{}
+	client, err := sentry.NewClient(sentry.ClientOptions{EnableTracing: true, Transport: transport})
+	s.Require().NoError(err)
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		Aggregator: aggregator,
+		Clock: func() time.Time {
+			return now
+		},
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		now = now.Add(time.Second)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	req = req.WithContext(sentry.SetHubOnContext(req.Context(), sentry.NewHub(client, sentry.NewScope())))
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	now = now.Add(time.Minute)
+	aggregator.Flush()
+
+	s.Empty(s.transport.Events())
+	s.Require().Len(transport.Events(), 1)
+	s.Equal(now, transport.Events()[0].Timestamp)
+}
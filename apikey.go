@@ -0,0 +1,43 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// APIKeyResolver resolves the owner of an API key, ok is false for unknown keys
+type APIKeyResolver func(c echo.Context, apiKey string) (user sentry.User, ok bool)
+
+// isAPIKeyHeader reports whether the header carries the API key, its value is never dumped
+func isAPIKeyHeader(config SentryConfig, name string) bool {
+	return config.APIKeyHeader != "" && http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(config.APIKeyHeader)
+}
+
+// dumpAPIKeyUser sets the owner of the API key as the user of the request scope
+func dumpAPIKeyUser(c echo.Context, config SentryConfig, span *sentry.Span) {
+	if config.APIKeyHeader == "" || config.APIKeyResolver == nil {
+		return
+	}
+
+	apiKey := c.Request().Header.Get(config.APIKeyHeader)
+	if apiKey == "" {
+		return
+	}
+
+	user, ok := config.APIKeyResolver(c, apiKey)
+	if !ok {
+		return
+	}
+
+	if hub := sentry.GetHubFromContext(span.Context()); hub != nil {
+		hub.Scope().SetUser(user)
+	}
+
+	setTag(span, config, "user.id", user.ID)
+
+	if user.Username != "" {
+		setTag(span, config, "user", user.Username)
+	}
+}
@@ -0,0 +1,66 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestAPIKeyUser() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors: true,
+		APIKeyHeader:  "X-Api-Key",
+		APIKeyResolver: func(_ echo.Context, apiKey string) (sentry.User, bool) {
+			if apiKey != "key" {
+				return sentry.User{}, false
+			}
+
+			return sentry.User{ID: "42", Username: "john"}, true
+		},
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return errors.New("test error")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Api-Key", "key")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Equal("42", span.Tags["user.id"])
+	s.Equal("john", span.Tags["user"])
+
+	events := s.errorEvents()
+	s.Len(events, 1)
+	s.Equal("42", events[0].User.ID)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Api-Key", "unknown")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+	s.Empty(span.Tags["user.id"])
+}
+
+func (s *MiddlewareTestSuite) TestAPIKeyHeaderMasked() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{AreHeadersDump: true, APIKeyHeader: "X-Api-Key"}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("x-api-key", "supersecretkey")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Equal(scrubbedValue, span.Tags["req.header.X-Api-Key"])
+
+	for name, value := range span.Tags {
+		s.NotContains(value, "supersecretkey", name)
+	}
+}
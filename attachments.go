@@ -0,0 +1,37 @@
+package echosentrymiddleware
+
+import (
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// AttachmentsFn returns attachments of the request, e.g. metadata of generated documents or debug dumps
+type AttachmentsFn func(c echo.Context) []*sentry.Attachment
+
+// dumpAttachments adds the attachments of AttachmentsFn to the request scope after the handler,
+// they are sent with the transaction and the events captured by the middleware
+func dumpAttachments(c echo.Context, config SentryConfig, span *sentry.Span) {
+	if config.AttachmentsFn == nil {
+		return
+	}
+
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil {
+		return
+	}
+
+	var names []string
+
+	for _, attachment := range config.AttachmentsFn(c) {
+		if attachment == nil {
+			continue
+		}
+
+		hub.Scope().AddAttachment(attachment)
+		names = append(names, attachment.Filename)
+	}
+
+	if len(names) > 0 {
+		span.SetData("attachments", names)
+	}
+}
@@ -0,0 +1,49 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestAttachments() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors: true,
+		AttachmentsFn: func(c echo.Context) []*sentry.Attachment {
+			report, ok := c.Get("report").(string)
+			if !ok {
+				return nil
+			}
+
+			return []*sentry.Attachment{nil, {Filename: "report.json", ContentType: echo.MIMEApplicationJSON, Payload: []byte(report)}}
+		},
+	}))
+	s.e.GET("/report", func(c echo.Context) error {
+		c.Set("report", `{"pages":3}`)
+		return errors.New("render failed")
+	})
+	s.e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, target := range []string{"/report", "/"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	events := s.transport.Events()
+	s.Require().Len(events, 3)
+
+	for _, event := range events[:2] {
+		s.Require().Len(event.Attachments, 1, event.Type)
+		s.Equal("report.json", event.Attachments[0].Filename)
+		s.Equal(`{"pages":3}`, string(event.Attachments[0].Payload))
+	}
+
+	s.Equal([]string{"report.json"}, events[1].Extra["attachments"])
+	s.Empty(events[2].Attachments)
+}
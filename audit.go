@@ -0,0 +1,22 @@
+package echosentrymiddleware
+
+import (
+	"slices"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// redactedDataKey is the span data listing what was redacted as "<where>:<key>", values are never recorded
+const redactedDataKey = "scrub.redacted"
+
+// auditRedaction records that the key was redacted, so reviewers can verify the scrubbers are firing
+func auditRedaction(span *sentry.Span, where, key string) {
+	entry := where + ":" + key
+
+	redacted, _ := span.Data[redactedDataKey].([]string)
+	if slices.Contains(redacted, entry) {
+		return
+	}
+
+	span.SetData(redactedDataKey, append(redacted, entry))
+}
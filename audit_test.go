@@ -0,0 +1,39 @@
+package echosentrymiddleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestRedactionAudit() {
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		AreHeadersDump: true,
+		IsBodyDump:     true,
+	}))
+	s.e.POST("/login", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login?token=abc", strings.NewReader("user=john&password=secret"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer abc")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.NotNil(span)
+	s.ElementsMatch([]string{
+		"request_uri:uri",
+		"req.header:Authorization",
+		"req.form:password",
+	}, span.Data[redactedDataKey])
+
+	// only keys are recorded, never values
+	s.NotContains(fmt.Sprint(span.Data[redactedDataKey]), "secret")
+}
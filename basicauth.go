@@ -0,0 +1,41 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// credentialHeaders contain credentials, only their auth scheme is dumped
+var credentialHeaders = []string{echo.HeaderAuthorization, "Proxy-Authorization"}
+
+// dumpBasicAuthUser tags the username of basic auth when enabled, the password is never read
+func dumpBasicAuthUser(span *sentry.Span, config SentryConfig, request *http.Request) {
+	if !config.TagBasicAuthUser {
+		return
+	}
+
+	if username, _, ok := request.BasicAuth(); ok {
+		setTag(span, config, "user", username)
+	}
+}
+
+// maskCredentials keeps only the auth scheme of credential headers, e.g. "Basic [scrubbed]"
+func maskCredentials(name, value string) string {
+	for _, header := range credentialHeaders {
+		if http.CanonicalHeaderKey(name) != header {
+			continue
+		}
+
+		scheme, _, found := strings.Cut(value, " ")
+		if !found {
+			return scrubbedValue
+		}
+
+		return scheme + " " + scrubbedValue
+	}
+
+	return value
+}
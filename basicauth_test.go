@@ -0,0 +1,53 @@
+package echosentrymiddleware
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestBasicAuth() {
+	const password = "s3cr3t-passw0rd"
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("john:" + password))
+
+	tests := []struct {
+		name   string
+		config SentryConfig
+		user   string
+	}{
+		{"header tags", SentryConfig{AreHeadersDump: true, TagBasicAuthUser: true}, "john"},
+		{"header data", SentryConfig{AreHeadersDump: true, HeadersAsData: true, TagBasicAuthUser: true}, "john"},
+		{"default no user", SentryConfig{AreHeadersDump: true}, ""},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var span *sentry.Span
+
+			e := echo.New()
+			e.Use(MiddlewareWithConfig(tt.config))
+			e.GET("/", func(c echo.Context) error {
+				span = sentry.TransactionFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.SetBasicAuth("john", password)
+			req.Header.Set("Proxy-Authorization", "Basic "+encoded)
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			s.NotNil(span)
+			s.Equal(tt.user, span.Tags["user"])
+
+			dumped := fmt.Sprint(span.Tags, span.Data)
+			s.NotContains(dumped, password)
+			s.NotContains(dumped, encoded)
+			s.Contains(dumped, "Basic "+scrubbedValue)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func BenchmarkMiddleware(b *testing.B) {
+	if err := sentry.Init(sentry.ClientOptions{
+		EnableTracing: true,
+		Transport:     &TransportMock{},
+	}); err != nil {
+		b.Fatal(err)
+	}
+
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42?page=2", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
@@ -0,0 +1,137 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func getMediaType(header http.Header) string {
+	mediaType, _, err := mime.ParseMediaType(header.Get(echo.HeaderContentType))
+	if err != nil {
+		return ""
+	}
+
+	return mediaType
+}
+
+// readReqBody reads the request body and resets it for the handler.
+// Read errors are reported to OnInternalError and the handler gets the read part followed by the rest of the body.
+func readReqBody(c echo.Context, config SentryConfig, request *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		reportInternalError(c, config, fmt.Errorf("%w: read request body: %w", ErrInternal, err))
+		request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), request.Body), request.Body}
+
+		return nil, false
+	}
+
+	if err := request.Body.Close(); err != nil {
+		reportInternalError(c, config, fmt.Errorf("%w: close request body: %w", ErrInternal, err))
+	}
+
+	request.Body = io.NopCloser(bytes.NewBuffer(body)) // reset original request body
+
+	return body, true
+}
+
+// peekReqBody reads up to limit bytes of the request body, the handler gets the read part followed by the rest
+func peekReqBody(request *http.Request, limit int) []byte {
+	peeked, _ := io.ReadAll(io.LimitReader(request.Body, int64(limit)))
+	request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peeked), request.Body), request.Body}
+
+	return peeked
+}
+
+// dumpReqBody records the request body according to its content type
+func dumpReqBody(span *sentry.Span, config SentryConfig, request *http.Request, body []byte) {
+	mediaType := getMediaType(request.Header)
+
+	switch {
+	case mediaType == echo.MIMEApplicationForm:
+		dumpFormBody(span, config, body)
+	case isXMLMediaType(mediaType):
+		dumpXMLBody(span, config, "req", request.Header, body)
+	case isProtobufMediaType(mediaType):
+		dumpProtobufBody(span, config, "req", request.URL.Path, len(body))
+	default:
+		setTag(span, config, "req.body", detectPII(span, config, "req.body", string(body)))
+	}
+}
+
+// dumpRespBody records the response body according to its content type
+func dumpRespBody(span *sentry.Span, config SentryConfig, request *http.Request, header http.Header, body string) {
+	if body == "" {
+		return
+	}
+
+	mediaType := getMediaType(header)
+
+	switch {
+	case isXMLMediaType(mediaType):
+		dumpXMLBody(span, config, "resp", header, []byte(body))
+	case isProtobufMediaType(mediaType):
+		dumpProtobufBody(span, config, "resp", request.URL.Path, len(body))
+	default:
+		setTag(span, config, "resp.body", detectPII(span, config, "resp.body", body))
+	}
+}
+
+// dumpFormBody records form fields as a map in span data
+func dumpFormBody(span *sentry.Span, config SentryConfig, body []byte) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		setTag(span, config, "req.body", detectPII(span, config, "req.body", string(body)))
+		return
+	}
+
+	for name, values := range form {
+		if isSensitive(name, config.SensitiveFields) {
+			for i := range values {
+				values[i] = scrubbedValue
+			}
+
+			auditRedaction(span, "req.form", name)
+
+			continue
+		}
+
+		for i := range values {
+			values[i] = detectPII(span, config, "req.form", escapeInvalidUTF8(values[i]))
+		}
+	}
+
+	span.SetData("req.form", form)
+}
+
+// attachRespBody adds the response body as an attachment of events sent during the request
+func attachRespBody(span *sentry.Span, header http.Header, body string, truncated bool) {
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil {
+		return
+	}
+
+	hub.Scope().AddAttachment(&sentry.Attachment{
+		Filename:    "response_body",
+		ContentType: header.Get(echo.HeaderContentType),
+		Payload:     []byte(body),
+	})
+
+	span.SetData("resp.body.attached", true)
+
+	if truncated {
+		span.SetData("resp.body.truncated", true)
+	}
+}
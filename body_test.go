@@ -0,0 +1,143 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestFormBody() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+
+	var span *sentry.Span
+	s.e.POST("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		s.Equal("john", c.FormValue("name"))
+		s.Equal("secret", c.FormValue("Password"))
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=john&Password=secret"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm+"; charset=utf-8")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.NotNil(span)
+	s.Empty(span.Tags["req.body"])
+	s.Equal(url.Values{"name": {"john"}, "Password": {scrubbedValue}}, span.Data["req.form"])
+}
+
+func (s *MiddlewareTestSuite) TestXMLBody() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+
+	var span *sentry.Span
+	s.e.POST("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.XMLBlob(http.StatusOK, []byte(`<result><status>ok</status></result>`))
+	})
+
+	reqBody := `<?xml version="1.0"?><Envelope><Body><Login user="john" token="abc"><Password>secret</Password></Login></Body></Envelope>`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMETextXMLCharsetUTF8)
+	req.Header.Set("SOAPAction", `"urn:Login"`)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.NotNil(span)
+	s.Empty(span.Tags["req.body"])
+	s.Equal("Envelope", span.Tags["req.xml.root"])
+	s.Equal("urn:Login", span.Tags["req.xml.action"])
+	snippet, _ := span.Data["req.xml.snippet"].(string)
+	s.Contains(snippet, `<Login user="john" token="[scrubbed]">`)
+	s.Contains(snippet, "<Password>[scrubbed]</Password>")
+	s.NotContains(snippet, "secret")
+
+	s.Empty(span.Tags["resp.body"])
+	s.Equal("result", span.Tags["resp.xml.root"])
+}
+
+func (s *MiddlewareTestSuite) TestProtobufBody() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+
+	var span *sentry.Span
+	s.e.POST("/*", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.Blob(http.StatusOK, "application/grpc-web+proto", []byte{0, 0, 0, 0, 1, 8})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/helloworld.Greeter/SayHello", strings.NewReader("\x00\x00\x00\x00\x03\x0a\x01a"))
+	req.Header.Set(echo.HeaderContentType, "application/grpc-web+proto")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.NotNil(span)
+	s.Empty(span.Tags["req.body"])
+	s.Empty(span.Tags["resp.body"])
+	s.Equal(8, span.Data["req.body.size"])
+	s.Equal(6, span.Data["resp.body.size"])
+	s.Equal("helloworld.Greeter", span.Tags["grpc.service"])
+	s.Equal("SayHello", span.Tags["grpc.method"])
+}
+
+func (s *MiddlewareTestSuite) TestRespDumpPolicy() {
+	tests := []struct {
+		name   string
+		policy RespDumpPolicy
+		check  func(span *sentry.Span)
+	}{
+		{
+			name:   "truncate",
+			policy: RespDumpTruncate,
+			check: func(span *sentry.Span) {
+				s.Equal("test", span.Tags["resp.body"])
+				s.Equal(true, span.Data["resp.body.truncated"])
+			},
+		},
+		{
+			name:   "drop",
+			policy: RespDumpDrop,
+			check: func(span *sentry.Span) {
+				s.Equal("[dropped]", span.Tags["resp.body"])
+			},
+		},
+		{
+			name:   "attach",
+			policy: RespDumpAttach,
+			check: func(span *sentry.Span) {
+				s.Empty(span.Tags["resp.body"])
+				s.Equal(true, span.Data["resp.body.attached"])
+
+				events := s.errorEvents()
+				s.Len(events, 1)
+				s.Len(events[0].Attachments, 1)
+				s.Equal("test", string(events[0].Attachments[0].Payload))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.transport.events = nil
+
+			e := echo.New()
+			e.Use(MiddlewareWithConfig(SentryConfig{
+				IsBodyDump:           true,
+				MaxRespDumpSize:      4,
+				RespDumpPolicy:       tt.policy,
+				CaptureEventOnStatus: []StatusRange{{From: 500, To: 599}},
+			}))
+
+			var span *sentry.Span
+			e.GET("/", func(c echo.Context) error {
+				span = sentry.TransactionFromContext(c.Request().Context())
+				return c.String(http.StatusInternalServerError, "testBody")
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			s.Equal("testBody", rec.Body.String())
+			tt.check(span)
+		})
+	}
+}
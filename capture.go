@@ -0,0 +1,97 @@
+package echosentrymiddleware
+
+import (
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// errorFingerprint identifies the same error for limiting and deduplication purposes
+func errorFingerprint(err error) string {
+	return fmt.Sprintf("%T: %s", err, err.Error())
+}
+
+// StatusRange is an inclusive range of HTTP statuses
+type StatusRange struct {
+	From int
+	To   int
+}
+
+// Contains reports whether the status is in the range
+func (r StatusRange) Contains(status int) bool {
+	return status >= r.From && status <= r.To
+}
+
+func shouldCaptureEvent(config SentryConfig, status int, err error) bool {
+	if len(config.CaptureEventOnStatus) == 0 {
+		return config.CaptureErrors && err != nil
+	}
+
+	for _, statusRange := range config.CaptureEventOnStatus {
+		if statusRange.Contains(status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eventError returns the error to be captured, responses written without an error get an HTTP error
+func eventError(status int, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return echo.NewHTTPError(status)
+}
+
+func captureError(c echo.Context, config SentryConfig, span *sentry.Span, err error) {
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil {
+		return
+	}
+
+	route := c.Path()
+	fingerprint := errorFingerprint(err)
+
+	capture := func(count int) {
+		sendError(hub, config.ErrorLimiter, route, fingerprint, err, count)
+	}
+
+	if config.ErrorDeduplicator != nil {
+		config.ErrorDeduplicator.add(route+"\x00"+fingerprint, capture)
+		return
+	}
+
+	capture(1)
+}
+
+func sendError(hub *sentry.Hub, limiter *ErrorLimiter, route, fingerprint string, err error, count int) {
+	var suppressed uint64
+
+	if limiter != nil {
+		var ok bool
+
+		ok, suppressed = limiter.allow(route, fingerprint)
+		if !ok {
+			return
+		}
+	}
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("path", route)
+
+		if suppressed > 0 {
+			scope.SetExtra("suppressed", suppressed)
+		}
+
+		if count > 1 {
+			scope.SetExtra("count", count)
+		}
+
+		if hub.CaptureException(err) != nil {
+			stats.errorsCaptured.Add(1)
+		}
+	})
+}
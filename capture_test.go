@@ -0,0 +1,106 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) errorEvents() []*sentry.Event {
+	var events []*sentry.Event
+
+	for _, event := range s.transport.Events() {
+		if event.Type != "transaction" {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func (s *MiddlewareTestSuite) TestCaptureErrors() {
+	limiter := NewErrorLimiter(0.001, 1)
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors: true,
+		ErrorLimiter:  limiter,
+	}))
+
+	s.e.GET("/error", func(echo.Context) error {
+		return errors.New("test error")
+	})
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/error", nil)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		s.Equal(http.StatusInternalServerError, rec.Code)
+	}
+
+	events := s.errorEvents()
+	s.Len(events, 1)
+	s.Equal("test error", events[0].Exception[0].Value)
+	s.Equal("/error", events[0].Tags["path"])
+	s.EqualValues(2, limiter.Suppressed())
+}
+
+func (s *MiddlewareTestSuite) TestCaptureErrorsDeduplicated() {
+	dedup := NewErrorDeduplicator(time.Hour)
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors:     true,
+		ErrorDeduplicator: dedup,
+	}))
+
+	s.e.GET("/error", func(echo.Context) error {
+		return errors.New("test error")
+	})
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/error", nil)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+	}
+
+	events := s.errorEvents()
+	s.Require().Len(events, 1)
+	s.NotContains(events[0].Extra, "count")
+
+	dedup.Flush()
+
+	events = s.errorEvents()
+	s.Require().Len(events, 2)
+	s.Equal(2, events[1].Extra["count"])
+}
+
+func (s *MiddlewareTestSuite) TestCaptureEventOnStatus() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureEventOnStatus: []StatusRange{
+			{From: http.StatusInternalServerError, To: 599},
+			{From: http.StatusTooManyRequests, To: http.StatusTooManyRequests},
+		},
+	}))
+
+	s.e.GET("/not-found", func(echo.Context) error {
+		return echo.ErrNotFound
+	})
+	s.e.GET("/too-many", func(c echo.Context) error {
+		return c.NoContent(http.StatusTooManyRequests)
+	})
+	s.e.GET("/unavailable", func(echo.Context) error {
+		return echo.ErrServiceUnavailable
+	})
+
+	for _, path := range []string{"/not-found", "/too-many", "/unavailable"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+	}
+
+	events := s.errorEvents()
+	s.Len(events, 2)
+	s.Equal("/too-many", events[0].Tags["path"])
+	s.Equal("/unavailable", events[1].Tags["path"])
+}
@@ -0,0 +1,60 @@
+package echosentrymiddleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+)
+
+// CardinalityGuard defines how high-cardinality values (UUIDs, long numbers, emails) are handled
+// in request_uri and path parameter tags
+type CardinalityGuard int
+
+const (
+	// CardinalityGuardOff keeps values as is
+	CardinalityGuardOff CardinalityGuard = iota
+	// CardinalityGuardPlaceholder replaces values with placeholders like {uuid}
+	CardinalityGuardPlaceholder
+	// CardinalityGuardHash replaces values with a short hash
+	CardinalityGuardHash
+)
+
+// minHighCardinalityDigits is the length of numbers considered to be IDs
+const minHighCardinalityDigits = 6
+
+var (
+	uuidPattern   = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+	emailPattern  = `[a-zA-Z0-9._%+-]+(?:@|%40)[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`
+	numberPattern = `\d{` + strconv.Itoa(minHighCardinalityDigits) + `,}`
+
+	highCardinalityRe = regexp.MustCompile(uuidPattern + `|` + emailPattern + `|` + numberPattern)
+	uuidRe            = regexp.MustCompile(`^` + uuidPattern + `$`)
+	emailRe           = regexp.MustCompile(`^` + emailPattern + `$`)
+)
+
+func guardCardinality(guard CardinalityGuard, value string) string {
+	if guard == CardinalityGuardOff {
+		return value
+	}
+
+	// single pass, so replacements are never matched again
+	return highCardinalityRe.ReplaceAllStringFunc(value, func(match string) string {
+		switch {
+		case guard == CardinalityGuardHash:
+			return hashValue(match)
+		case uuidRe.MatchString(match):
+			return "{uuid}"
+		case emailRe.MatchString(match):
+			return "{email}"
+		default:
+			return "{number}"
+		}
+	})
+}
+
+func hashValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,16 @@
+package echosentrymiddleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardCardinality(t *testing.T) {
+	uri := "/users/123456/orders/0b6f5a34-5c36-4a8d-a0a8-9b1c7bb3f0a4?email=john%40example.com&page=2"
+
+	require.Equal(t, uri, guardCardinality(CardinalityGuardOff, uri))
+	require.Equal(t, "/users/{number}/orders/{uuid}?email={email}&page=2", guardCardinality(CardinalityGuardPlaceholder, uri))
+	require.Equal(t, "/users/"+hashValue("123456")+"/orders/"+hashValue("0b6f5a34-5c36-4a8d-a0a8-9b1c7bb3f0a4")+
+		"?email="+hashValue("john%40example.com")+"&page=2", guardCardinality(CardinalityGuardHash, uri))
+}
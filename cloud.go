@@ -0,0 +1,24 @@
+package echosentrymiddleware
+
+import "github.com/getsentry/sentry-go"
+
+// CloudMetadata describes where the instance runs, for fleets spanning multiple regions.
+// AvailabilityZone is tagged as server.availability_zone, taking precedence over the zone of ServerIdentity.
+type CloudMetadata struct {
+	Provider         string
+	Region           string
+	AvailabilityZone string
+	InstanceType     string
+}
+
+// CloudMetadataProvider returns the cloud metadata of the instance, e.g. from the metadata service
+// of the provider. It is called once on middleware creation.
+type CloudMetadataProvider func() CloudMetadata
+
+func dumpCloudMetadata(span *sentry.Span, config SentryConfig) {
+	metadata := config.cloudMetadata
+
+	setTag(span, config, "cloud.provider", metadata.Provider)
+	setTag(span, config, "cloud.region", metadata.Region)
+	setTag(span, config, "cloud.instance_type", metadata.InstanceType)
+}
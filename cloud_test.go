@@ -0,0 +1,66 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestCloudMetadata() {
+	calls := 0
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CloudMetadata: func() CloudMetadata {
+			calls++
+
+			return CloudMetadata{
+				Provider:         "aws",
+				Region:           "eu-west-1",
+				AvailabilityZone: "eu-west-1a",
+				InstanceType:     "m5.large",
+			}
+		},
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Equal(1, calls)
+	s.Equal("aws", span.Tags["cloud.provider"])
+	s.Equal("eu-west-1", span.Tags["cloud.region"])
+	s.Equal("eu-west-1a", span.Tags["server.availability_zone"])
+	s.NotContains(span.Tags, "cloud.availability_zone")
+	s.Equal("m5.large", span.Tags["cloud.instance_type"])
+}
+
+func (s *MiddlewareTestSuite) TestCloudMetadataAvailabilityZone() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		ServerIdentity: func() ServerIdentity {
+			return ServerIdentity{Hostname: "web-1", AvailabilityZone: "from-env"}
+		},
+		CloudMetadata: func() CloudMetadata {
+			return CloudMetadata{Provider: "aws", AvailabilityZone: "eu-west-1a"}
+		},
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Require().NotNil(span)
+	s.Equal("web-1", span.Tags["server.hostname"])
+	s.Equal("eu-west-1a", span.Tags["server.availability_zone"])
+	s.NotContains(span.Tags, "cloud.availability_zone")
+}
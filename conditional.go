@@ -0,0 +1,30 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerIfNoneMatch = "If-None-Match"
+	headerETag        = "ETag"
+)
+
+// isConditional reports whether the request revalidates a cached response
+func isConditional(request *http.Request) bool {
+	return request.Header.Get(headerIfNoneMatch) != "" || request.Header.Get(echo.HeaderIfModifiedSince) != ""
+}
+
+// dumpConditional tags cache revalidations with their outcome and the ETag, so cache efficiency is visible per route
+func dumpConditional(c echo.Context, config SentryConfig, span *sentry.Span, status int) {
+	if !isConditional(c.Request()) {
+		return
+	}
+
+	setTag(span, config, "cache.revalidation", "true")
+	setTag(span, config, "cache.not_modified", strconv.FormatBool(status == http.StatusNotModified))
+	setTag(span, config, "resp.etag", c.Response().Header().Get(headerETag))
+}
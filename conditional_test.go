@@ -0,0 +1,51 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestConditionalRequest() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		c.Response().Header().Set(headerETag, `"v2"`)
+
+		if c.Request().Header.Get(headerIfNoneMatch) == `"v2"` {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return c.String(http.StatusOK, "content")
+	})
+
+	tests := []struct {
+		name        string
+		etag        string
+		notModified string
+		body        string
+	}{
+		{name: "not modified", etag: `"v2"`, notModified: "true"},
+		{name: "modified", etag: `"v1"`, notModified: "false", body: "content"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(headerIfNoneMatch, tt.etag)
+			s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+			s.Equal("true", span.Tags["cache.revalidation"])
+			s.Equal(tt.notModified, span.Tags["cache.not_modified"])
+			s.Equal(`"v2"`, span.Tags["resp.etag"])
+			s.Equal(tt.body, span.Tags["resp.body"])
+		})
+	}
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	s.NotContains(span.Tags, "cache.revalidation")
+}
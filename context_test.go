@@ -0,0 +1,69 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+type contextKey struct{}
+
+func (s *MiddlewareTestSuite) TestContextRestore() {
+	tests := []struct {
+		name    string
+		handler echo.HandlerFunc
+		replace bool
+	}{
+		{
+			name: "request kept",
+			handler: func(c echo.Context) error {
+				s.NotNil(sentry.SpanFromContext(c.Request().Context()))
+				return c.NoContent(http.StatusOK)
+			},
+		},
+		{
+			name: "request replaced",
+			handler: func(c echo.Context) error {
+				ctx := context.WithValue(c.Request().Context(), contextKey{}, "value")
+				c.SetRequest(c.Request().WithContext(ctx))
+
+				return c.NoContent(http.StatusOK)
+			},
+			replace: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var original *http.Request
+
+			e := echo.New()
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					original = c.Request()
+					err := next(c)
+
+					// nothing of the middleware leaks to the outer middleware
+					s.Nil(sentry.SpanFromContext(c.Request().Context()))
+					s.Nil(sentry.GetHubFromContext(c.Request().Context()))
+					s.Equal(original.Context(), c.Request().Context())
+
+					if tt.replace {
+						s.NotSame(original, c.Request())
+					} else {
+						s.Same(original, c.Request())
+					}
+
+					return err
+				}
+			})
+			e.Use(Middleware())
+			e.GET("/", tt.handler)
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package echosentrymiddleware
+
+import (
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// ContextsFn returns named contexts of the request, e.g. "payment" or "device", shown as structured sections of events
+type ContextsFn func(c echo.Context) map[string]sentry.Context
+
+// dumpContexts sets the contexts of ContextsFn on the request scope before the handler, so the transaction
+// and the events captured during the request carry them. The "trace" context is reserved for the transaction.
+func dumpContexts(c echo.Context, config SentryConfig, span *sentry.Span) {
+	if config.ContextsFn == nil {
+		return
+	}
+
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil {
+		return
+	}
+
+	for name, values := range config.ContextsFn(c) {
+		if name == "" || name == "trace" || len(values) == 0 {
+			continue
+		}
+
+		hub.Scope().SetContext(name, values)
+	}
+}
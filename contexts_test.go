@@ -0,0 +1,39 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestContexts() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors: true,
+		ContextsFn: func(c echo.Context) map[string]sentry.Context {
+			return map[string]sentry.Context{
+				"payment": {"provider": "stripe", "currency": c.QueryParam("currency")},
+				"trace":   {"trace_id": "ignored"},
+				"empty":   {},
+			}
+		},
+	}))
+	s.e.GET("/pay", func(c echo.Context) error {
+		return errors.New("payment failed")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/pay?currency=EUR", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Require().Len(events, 2)
+
+	for _, event := range events {
+		s.Equal(sentry.Context{"provider": "stripe", "currency": "EUR"}, event.Contexts["payment"], event.Type)
+		s.NotContains(event.Contexts, "empty")
+		s.NotEqual("ignored", event.Contexts["trace"]["trace_id"])
+	}
+}
@@ -0,0 +1,465 @@
+package echosentrymiddleware
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// handleRequest is the core of the middleware shared by the echo and net/http variants.
+// Telemetry code runs in safe blocks, so its failures never break the request.
+func handleRequest(c echo.Context, config SentryConfig, next echo.HandlerFunc) error {
+	var (
+		request *http.Request
+		span    *sentry.Span
+		endSpan func()
+	)
+
+	// nothing would be sent without a client, so the request is only passed through
+	if !isClientBound(c.Request()) {
+		return next(c)
+	}
+
+	// an outer registration already captures and handles the errors of the request
+	nested := isHandled(c)
+	if nested && config.NestedPolicy == NestedSkip {
+		return next(c)
+	}
+
+	var (
+		mirror   *mirroredSpan
+		status   int
+		err      error
+		timedOut bool
+	)
+
+	safely(c, config, "mirrored span creation", func() { mirror = startMirroredSpan(c, config) })
+	defer safely(c, config, "mirrored span finish", func() { mirror.end(c, status, err, timedOut) })
+
+	// assets of echo's Static and File routes are traced by route and their bodies are never dumped
+	static := config.staticRoutes.get(c)
+
+	if !safely(c, config, "span creation", func() { request, span, endSpan = createSpan(c, config, static) }) {
+		return next(c)
+	}
+
+	defer safely(c, config, "span finish", endSpan)
+
+	if config.FinishAfterResponse {
+		trackResponseWrites(c, config, span)
+		defer safely(c, config, "response flush", func() {
+			if !timedOut {
+				flushResponse(c)
+			}
+		})
+	}
+
+	upload := traceUpload(config, span, request)
+	defer safely(c, config, "upload finish", upload.finish)
+
+	span.SetData("http.in_flight", inFlightRequests.Add(1))
+	defer inFlightRequests.Add(-1)
+
+	var (
+		skipReqBody, skipRespBody bool
+		respDumper                *bodyDumper
+		streamed                  *streamedKind
+	)
+
+	safely(c, config, "request dump", func() {
+		dumpClientIP(c, config, span)
+		setTag(span, config, "remote_addr", request.RemoteAddr)
+		if config.OmitRequestURI {
+			dumpQuery(span, config, request)
+		} else {
+			requestURI := config.URLScrubber(request.RequestURI)
+			if requestURI != request.RequestURI {
+				auditRedaction(span, "request_uri", "uri")
+			}
+
+			setTag(span, config, "request_uri", guardCardinality(config.CardinalityGuard, requestURI))
+		}
+
+		setTag(span, config, "path", c.Path())
+
+		if config.AddFingerprintTag {
+			setTag(span, config, "fingerprint", getRequestFingerprint(request, c.Path()))
+		}
+
+		if config.AddVersionTags {
+			dumpVersions(span, config)
+		}
+
+		if config.AddBuildInfo {
+			dumpBuildInfo(span, config)
+		}
+
+		dumpServerIdentity(span, config)
+		dumpKubernetesMetadata(span, config)
+		dumpCloudMetadata(span, config)
+
+		skipReqBody, skipRespBody = config.BodySkipper(c)
+
+		if static != notStatic {
+			dumpStaticRoute(c, config, span, static)
+			skipReqBody, skipRespBody = true, true
+		}
+
+		respDumper = dumpReq(c, config, span, request, skipReqBody)
+		streamed = trackStreamedResponse(c, config, span, respDumper)
+		dumpRequestToScope(span)
+	})
+
+	// read before the handler, the context of an abandoned handler is not inspected afterwards
+	path := c.Path()
+
+	// call next middleware / controller
+	timedOut, err = callNext(c, config, span, request, next)
+	if err != nil {
+		setTag(span, config, "echo.error", err.Error())
+	}
+
+	// call custom registered error handler
+	result := err
+	if !nested {
+		result = handleErrorInSpan(c, config, span, timedOut, err)
+	}
+
+	safely(c, config, "response dump", func() {
+		// the abandoned handler still uses the context, so only the span is updated
+		if timedOut {
+			status = dumpTimeout(config, span, request)
+		} else {
+			status = dumpResp(c, config, span, respDumper, streamed, skipRespBody, err)
+			dumpAttachments(c, config, span)
+			dumpContextTags(c, config, span)
+			dumpWebhookSignature(c, config, span)
+		}
+
+		dumpSlowRequest(config, span, config.Clock().Sub(span.StartTime))
+
+		if !timedOut {
+			dumpResponseToScope(c, config, span, request, status)
+		}
+
+		if !timedOut && !nested {
+			if shouldCaptureEvent(config, status, err) {
+				captureError(c, config, span, eventError(status, err))
+			}
+
+			captureSecurityEvent(c, config, span, status)
+		}
+
+		applyParentSampling(config.ParentSampling, span, request, status, err)
+		aggregateRequest(config, span, request.Method, path, status, err)
+		applyTransactionQuota(config, span, path)
+	})
+
+	return result
+}
+
+// callNext calls the rest of the chain (middleware registered after this one and the handler) inside of a child span
+// and reports whether the request timed out in echo's Timeout middleware
+func callNext(
+	c echo.Context, config SentryConfig, span *sentry.Span, request *http.Request, next echo.HandlerFunc,
+) (timedOut bool, err error) {
+	// started before the handler span pushes its scope, so the breadcrumbs outlive it
+	defer startHeartbeat(c, config, span)()
+
+	handlerSpan := span.StartChild("http.handler", sentry.WithDescription(c.Path()), idOption(config.IDGenerator))
+	handlerSpan.StartTime = config.Clock()
+	defer finishWithClock(handlerSpan, config.Clock)()
+
+	// setup request context - add span, it is injected once and removed as soon as the chain returns
+	injected := request.WithContext(handlerContext(config, request, markHandled(handlerSpan.Context(), config)))
+	c.SetRequest(injected)
+
+	detector := newTimeoutDetector(c)
+	c.Response().Writer = exposeOptional(detector)
+
+	defer func() {
+		// the abandoned handler still uses the context, so it is left as is
+		if detector.timedOut {
+			return
+		}
+
+		if unexposeOptional(c.Response().Writer) == detector {
+			c.Response().Writer = detector.ResponseWriter
+		}
+
+		restoreRequest(c, request, injected)
+	}()
+	defer recordHandlerDuration(span, config, config.Clock())
+
+	err = next(c)
+
+	return detector.timedOut, err
+}
+
+// restoreRequest removes the span from the request context, so it doesn't leak to the outer middleware.
+// The original request is reused unless the chain replaced the injected one.
+func restoreRequest(c echo.Context, original, injected *http.Request) {
+	if current := c.Request(); current != injected {
+		c.SetRequest(current.WithContext(original.Context()))
+		return
+	}
+
+	c.SetRequest(original)
+}
+
+// getClientIP returns the client IP and, with TrustedProxies, the chain of forwarding proxies
+func getClientIP(c echo.Context, config SentryConfig) (string, []string) {
+	if len(config.trustedProxies) > 0 {
+		return resolveClientIP(c.Request(), config.trustedProxies)
+	}
+
+	if config.IPExtractor != nil {
+		return config.IPExtractor(c.Request()), nil
+	}
+
+	return c.RealIP(), nil
+}
+
+func dumpClientIP(c echo.Context, config SentryConfig, span *sentry.Span) {
+	clientIP, chain := getClientIP(c, config)
+	setTag(span, config, "client_ip", clientIP)
+
+	if len(chain) > 0 {
+		span.SetData("forwarded_for", chain)
+	}
+}
+
+func dumpResp(
+	c echo.Context, config SentryConfig, span *sentry.Span, respDumper *bodyDumper, streamed *streamedKind,
+	skipRespBody bool, err error,
+) int {
+	setTag(span, config, "request_id", getRequestID(c))
+
+	status, spanStatus := getResponseStatus(c, err)
+	span.Status = spanStatus
+	setTag(span, config, "resp.status", strconv.Itoa(status))
+
+	dumpCORSResp(span, config, c.Request(), c.Response().Header())
+	dumpEncoding(span, config, "resp", c.Response().Header())
+	dumpRateLimit(c, config, span, status)
+	dumpRetryHeaders(span, "resp", c.Response().Header())
+	dumpRedirect(c, config, span, status)
+	dumpConditional(c, config, span, status)
+	dumpRanges(c, span, status)
+	dumpRPC(c, config, span)
+
+	// Dump response headers
+	dumpHeaders(span, config, "resp", "http.response.headers", c.Response().Header())
+
+	isStreamed := dumpStreamed(c, config, span, streamed)
+
+	// Dump response body
+	// 304 responses have no body, bodies of files and streams are not captured
+	if config.IsBodyDump && status != http.StatusNotModified && !isStreamed &&
+		(!config.DumpRespBodyOnErrorOnly || status >= http.StatusBadRequest) {
+		finishDump := startDumpSpan(span, config, "dump response body")
+		defer finishDump()
+
+		respBody := respDumper.GetResponse()
+
+		switch {
+		case respBody == "" && c.Response().Size > 0:
+			// the body bypassed the dumpers, e.g. it was buffered by another writer wrapper
+			setTag(span, config, "resp.body.capture", "unavailable")
+		case respBody != "" && skipRespBody:
+			setTag(span, config, "resp.body", "[excluded]")
+		case respDumper.Overflow() && config.RespDumpPolicy == RespDumpDrop:
+			setTag(span, config, "resp.body", "[dropped]")
+		case respBody != "" && config.RespDumpPolicy == RespDumpAttach:
+			attachRespBody(span, c.Response().Header(), respBody, respDumper.Overflow())
+			config.Metrics.BodyDumped("resp", len(respBody))
+		default:
+			if respDumper.Overflow() {
+				stats.truncations.Add(1)
+				span.SetData("resp.body.truncated", true)
+			}
+
+			start := time.Now()
+			dumpRespBody(span, config, c.Request(), c.Response().Header(), respBody)
+			config.Metrics.BodyScrubbed(time.Since(start))
+			config.Metrics.BodyDumped("resp", len(respBody))
+		}
+	}
+
+	return status
+}
+
+func dumpReq(c echo.Context, config SentryConfig, span *sentry.Span, request *http.Request, skipReqBody bool) *bodyDumper {
+	dumpBasicAuthUser(span, config, request)
+
+	dumpAPIKeyUser(c, config, span)
+	dumpFlags(c, config, span)
+	dumpContexts(c, config, span)
+	dumpExperiments(c, config, span)
+
+	// Add path parameters
+	for _, paramName := range limitNames(span, "path.dropped", config.MaxParamTags, c.ParamNames()) {
+		setTag(span, config, "path."+paramName, guardCardinality(config.CardinalityGuard, c.Param(paramName)))
+	}
+
+	dumpTLS(span, request.TLS)
+	dumpQueueTime(span, config, request.Header)
+	dumpRetryHeaders(span, "req", request.Header)
+	dumpCORSReq(span, config, request)
+	dumpEncoding(span, config, "req", request.Header)
+
+	// Headers summary is recorded even without dumping
+	headersCount, headersSize := getHeadersSummary(request.Header)
+	span.SetData("req.headers.count", headersCount)
+	span.SetData("req.headers.size", headersSize)
+
+	// Dump request headers
+	dumpHeaders(span, config, "req", "http.request.headers", request.Header)
+
+	// Dump request & response body
+	var respDumper *bodyDumper
+
+	if config.IsBodyDump {
+		// request
+		if request.Body != nil && slices.Contains(config.DumpReqBodyMethods, request.Method) {
+			if skipReqBody {
+				setTag(span, config, "req.body", "[excluded]")
+			} else {
+				finishRead := startDumpSpan(span, config, "read request body")
+
+				reqBody, ok := readReqBody(c, config, request)
+
+				finishRead()
+
+				if ok {
+					start := time.Now()
+					finishDump := startDumpSpan(span, config, "dump request body")
+					dumpReqBody(span, config, request, reqBody)
+					finishDump()
+					config.Metrics.BodyScrubbed(time.Since(start))
+					config.Metrics.BodyDumped("req", len(reqBody))
+				}
+			}
+		}
+
+		// response
+		respDumper = newBodyDumper(c.Response().Writer, config.MaxRespDumpSize)
+		c.Response().Writer = exposeOptional(respDumper)
+		tapForeignWriter(c, span, respDumper)
+
+		if config.DumpRespBodyOnErrorOnly {
+			resp := c.Response()
+			resp.Before(func() {
+				// status is already known here, but nothing is written yet
+				if resp.Status < http.StatusBadRequest {
+					respDumper.SkipBody()
+				}
+			})
+		}
+	}
+
+	return respDumper
+}
+
+func createSpan(c echo.Context, config SentryConfig, static staticRoute) (*http.Request, *sentry.Span, func()) {
+	request := c.Request()
+
+	tname := "HTTP " + request.Method + " " + config.URLScrubber(request.RequestURI)
+	source := sentry.SourceURL
+
+	var routeName string
+	if c.Echo() != nil && (config.UseRouteName || strings.Contains(config.OpNameTemplate, "{route_name}")) {
+		routeName = config.routeNames.get(c)
+	}
+
+	opname := formatOpName(config, c, routeName)
+	tenant := getTenant(c, config)
+
+	if config.OmitRequestURI || static != notStatic {
+		tname = "HTTP " + request.Method + " " + getRoute(c, config)
+		source = sentry.SourceRoute
+	}
+
+	if rpcName := getRPCName(c, config, request); rpcName != "" {
+		tname = rpcName
+		source = sentry.SourceRoute
+	}
+
+	jsonRPCMethods := peekJSONRPCMethods(c, config, request)
+	if rpcName := getJSONRPCName(config, jsonRPCMethods); rpcName != "" {
+		tname = rpcName
+		source = sentry.SourceCustom
+	}
+
+	soapAction, soapOperation := getSOAPName(c, config, request)
+	if soapName := cmp.Or(soapAction, soapOperation); soapName != "" {
+		tname = soapName
+		source = sentry.SourceCustom
+	}
+
+	if config.UseRouteName && routeName != "" {
+		tname = routeName
+		source = sentry.SourceCustom
+	}
+
+	// a transaction is already running (e.g. the middleware is registered twice or behind sentryhttp),
+	// so a child span is added instead of a second root transaction
+	if parent := sentry.SpanFromContext(request.Context()); parent != nil {
+		span := parent.StartChild(opname, sentry.WithDescription(tname), idOption(config.IDGenerator))
+		span.StartTime = config.Clock()
+		setTag(span, config, "route.name", routeName)
+		dumpJSONRPC(span, config, jsonRPCMethods)
+		dumpSOAP(span, config, soapAction, soapOperation)
+		dumpTenant(span, config, nil, tenant)
+
+		return request, span, func() {
+			span.EndTime = config.Clock()
+			recordTotalDuration(span)
+			span.Finish()
+		}
+	}
+
+	// request scoped hub for the events sent during the request
+	hub := getHub(request)
+
+	hub.Scope().SetRequest(request)
+	overrideEnvironment(hub, config.Environment, config.Release)
+	scrubEventQuery(hub, config.URLScrubber)
+
+	span := sentry.StartSpan(sentry.SetHubOnContext(withEchoContext(spanParentContext(config, request), c), hub), opname,
+		sentry.WithTransactionName(tname),
+		sentry.WithTransactionSource(source),
+		idOption(config.IDGenerator),
+		sentry.ContinueFromRequest(request),
+		parentSamplingOption(config.ParentSampling),
+		tenantSampleOption(config, tenant),
+		debugSampleOption(config, request),
+	)
+
+	span.StartTime = config.Clock()
+
+	setTag(span, config, "route.name", routeName)
+	dumpJSONRPC(span, config, jsonRPCMethods)
+	dumpSOAP(span, config, soapAction, soapOperation)
+	dumpTenant(span, config, hub, tenant)
+	config.Metrics.TransactionStarted()
+
+	return request, span, func() {
+		span.EndTime = config.Clock()
+		recordTotalDuration(span)
+
+		config.Metrics.TransactionFinished(span.Sampled.Bool())
+
+		if span.Sampled.Bool() {
+			stats.transactionsSampled.Add(1)
+		}
+
+		defer span.Finish()
+	}
+}
@@ -0,0 +1,38 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func isPreflight(request *http.Request) bool {
+	return request.Method == http.MethodOptions && request.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+}
+
+// dumpCORSReq tags cross-origin requests, even without headers dumping
+func dumpCORSReq(span *sentry.Span, config SentryConfig, request *http.Request) {
+	origin := request.Header.Get(echo.HeaderOrigin)
+	if origin == "" {
+		return
+	}
+
+	setTag(span, config, "cors.origin", origin)
+	setTag(span, config, "cors.preflight", strconv.FormatBool(isPreflight(request)))
+}
+
+// dumpCORSResp tags the Allow-Origin decision for cross-origin requests
+func dumpCORSResp(span *sentry.Span, config SentryConfig, request *http.Request, header http.Header) {
+	if request.Header.Get(echo.HeaderOrigin) == "" {
+		return
+	}
+
+	allowOrigin := header.Get(echo.HeaderAccessControlAllowOrigin)
+	if allowOrigin == "" {
+		allowOrigin = "[not allowed]"
+	}
+
+	setTag(span, config, "cors.allow_origin", allowOrigin)
+}
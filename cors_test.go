@@ -0,0 +1,49 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func (s *MiddlewareTestSuite) TestCORS() {
+	var span *sentry.Span
+
+	s.e.Use(Middleware())
+	s.e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return next(c)
+		}
+	})
+	s.e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins: []string{"https://allowed.com"},
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.Run("preflight", func() {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://allowed.com")
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+		s.Equal("https://allowed.com", span.Tags["cors.origin"])
+		s.Equal("true", span.Tags["cors.preflight"])
+		s.Equal("https://allowed.com", span.Tags["cors.allow_origin"])
+	})
+
+	s.Run("not allowed", func() {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://denied.com")
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+		s.Equal("https://denied.com", span.Tags["cors.origin"])
+		s.Equal("false", span.Tags["cors.preflight"])
+		s.Equal("[not allowed]", span.Tags["cors.allow_origin"])
+	})
+}
@@ -0,0 +1,29 @@
+package echosentrymiddleware
+
+import (
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// dumpContextTags records values stored with c.Set as tags. It runs after the handler,
+// so values set by middleware registered after this one are recorded too.
+func dumpContextTags(c echo.Context, config SentryConfig, span *sentry.Span) {
+	for key, tag := range config.ContextTags {
+		value := c.Get(key)
+		if value == nil {
+			continue
+		}
+
+		if tag == "" {
+			tag = key
+		}
+
+		if s, ok := value.(string); ok {
+			setTag(span, config, tag, s)
+		} else {
+			setTag(span, config, tag, fmt.Sprint(value))
+		}
+	}
+}
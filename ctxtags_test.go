@@ -0,0 +1,40 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestContextTags() {
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		ContextTags: map[string]string{
+			"org_id":      "org.id",
+			"shard":       "",
+			"auth_scheme": "auth.scheme",
+		},
+	}))
+	s.e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("org_id", "acme")
+			c.Set("shard", 7)
+
+			return next(c)
+		}
+	})
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.NotNil(span)
+	s.Equal("acme", span.Tags["org.id"])
+	s.Equal("7", span.Tags["shard"])
+	s.NotContains(span.Tags, "auth.scheme")
+}
@@ -0,0 +1,40 @@
+package echosentrymiddleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// DefaultDebugSampleHeader is the default header forcing sampling of a request, see DebugSampleSecret
+const DefaultDebugSampleHeader = "X-Sentry-Force-Trace"
+
+// isDebugSampled reports whether the request carries the debug sample header with the shared secret
+func isDebugSampled(config SentryConfig, request *http.Request) bool {
+	if config.DebugSampleSecret == "" {
+		return false
+	}
+
+	value := request.Header.Get(config.DebugSampleHeader)
+
+	return value != "" && subtle.ConstantTimeCompare([]byte(value), []byte(config.DebugSampleSecret)) == 1
+}
+
+// debugSampleOption samples the transaction of requests forced with the debug sample header,
+// regardless of the sample rate and the upstream decision (nothing is sent if tracing is disabled in the SDK)
+func debugSampleOption(config SentryConfig, request *http.Request) sentry.SpanOption {
+	return func(span *sentry.Span) {
+		if !isDebugSampled(config, request) {
+			return
+		}
+
+		span.Sampled = sentry.SampledTrue
+		setTag(span, config, "debug.forced", "true")
+	}
+}
+
+// isDebugSampleHeader reports whether the header carries the debug sample secret, it is never dumped
+func isDebugSampleHeader(config SentryConfig, name string) bool {
+	return config.DebugSampleSecret != "" && http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(config.DebugSampleHeader)
+}
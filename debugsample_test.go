@@ -0,0 +1,71 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestDebugSample() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		AreHeadersDump:    true,
+		DebugSampleSecret: "secret",
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		sampled sentry.Sampled
+	}{
+		{name: "no header", sampled: sentry.SampledFalse},
+		{name: "wrong secret", header: DefaultDebugSampleHeader, value: "guess", sampled: sentry.SampledFalse},
+		{name: "forced", header: DefaultDebugSampleHeader, value: "secret", sampled: sentry.SampledTrue},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(sentry.SentryTraceHeader, unsampledTrace)
+
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+			s.Equal(tt.sampled, span.Sampled)
+
+			if tt.header != "" {
+				s.Equal(scrubbedValue, span.Tags["req.header."+tt.header])
+			}
+		})
+	}
+
+	s.Equal("true", span.Tags["debug.forced"])
+}
+
+func (s *MiddlewareTestSuite) TestDebugSampleWithoutSecret() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{AreHeadersDump: true}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultDebugSampleHeader, "anything")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Equal(sentry.SampledFalse, span.Sampled)
+	s.Empty(span.Tags["debug.forced"])
+}
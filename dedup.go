@@ -0,0 +1,95 @@
+package echosentrymiddleware
+
+import (
+	"sync"
+	"time"
+)
+
+// maxDedupPending limits memory used by the deduplicator on many distinct errors
+const maxDedupPending = 10000
+
+// ErrorDeduplicator merges identical errors (same route, type and message) captured within a window.
+// The first occurrence is sent right away, repeats are sent as a single event with a "count" extra
+// when the window is over.
+type ErrorDeduplicator struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingError
+}
+
+type pendingError struct {
+	repeats int
+	timer   *time.Timer
+	capture func(count int)
+}
+
+// NewErrorDeduplicator returns a deduplicator with the given window
+func NewErrorDeduplicator(window time.Duration) *ErrorDeduplicator {
+	return &ErrorDeduplicator{
+		window:  window,
+		pending: make(map[string]*pendingError),
+	}
+}
+
+// Flush sends the repeats counted so far immediately, call it before the application exits
+func (d *ErrorDeduplicator) Flush() {
+	d.mu.Lock()
+	pending := d.pending
+	d.pending = make(map[string]*pendingError)
+	d.mu.Unlock()
+
+	flushPending(pending)
+}
+
+// flushPending stops the timers of errors no longer tracked and sends their repeats,
+// errors whose timer already fired are sent by emit
+func flushPending(pending map[string]*pendingError) {
+	for _, p := range pending {
+		if p.timer.Stop() && p.repeats > 0 {
+			p.capture(p.repeats)
+		}
+	}
+}
+
+func (d *ErrorDeduplicator) add(key string, capture func(count int)) {
+	d.mu.Lock()
+
+	if p, ok := d.pending[key]; ok {
+		p.repeats++
+		d.mu.Unlock()
+
+		return
+	}
+
+	// dropped errors send their repeats now instead of keeping their timers
+	var dropped map[string]*pendingError
+	if len(d.pending) >= maxDedupPending {
+		dropped = d.pending
+		d.pending = make(map[string]*pendingError)
+	}
+
+	p := &pendingError{capture: capture}
+	p.timer = time.AfterFunc(d.window, func() {
+		d.emit(key, p)
+	})
+	d.pending[key] = p
+	d.mu.Unlock()
+
+	flushPending(dropped)
+	capture(1)
+}
+
+func (d *ErrorDeduplicator) emit(key string, p *pendingError) {
+	d.mu.Lock()
+	if d.pending[key] == p {
+		delete(d.pending, key)
+	}
+
+	repeats := p.repeats
+	d.mu.Unlock()
+
+	if repeats > 0 {
+		p.capture(repeats)
+	}
+}
@@ -0,0 +1,66 @@
+package echosentrymiddleware
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorDeduplicator(t *testing.T) {
+	dedup := NewErrorDeduplicator(10 * time.Millisecond)
+	counts := make(chan int, 2)
+
+	for range 3 {
+		dedup.add("key", func(count int) { counts <- count })
+	}
+
+	// the first occurrence is not delayed
+	require.Equal(t, 1, <-counts)
+
+	select {
+	case count := <-counts:
+		require.Equal(t, 2, count)
+	case <-time.After(time.Second):
+		require.Fail(t, "repeats were not emitted")
+	}
+
+	dedup.add("key", func(count int) { counts <- count })
+	dedup.add("key", func(count int) { counts <- count })
+	require.Equal(t, 1, <-counts)
+
+	dedup.Flush()
+	require.Equal(t, 1, <-counts)
+	require.Empty(t, counts)
+}
+
+func TestErrorDeduplicatorLimit(t *testing.T) {
+	dedup := NewErrorDeduplicator(time.Hour)
+
+	var repeats []int
+	capture := func(count int) {
+		if count > 1 {
+			repeats = append(repeats, count)
+		}
+	}
+
+	for i := range maxDedupPending {
+		dedup.add(strconv.Itoa(i), capture)
+	}
+
+	dedup.add("0", capture)
+	dedup.add("0", capture)
+	require.Empty(t, repeats)
+
+	// dropped errors send their repeats right away and their timers are stopped
+	dedup.add(strconv.Itoa(maxDedupPending), capture)
+	require.Equal(t, []int{2}, repeats)
+
+	dedup.mu.Lock()
+	require.Len(t, dedup.pending, 1)
+	dedup.mu.Unlock()
+
+	dedup.Flush()
+	require.Equal(t, []int{2}, repeats)
+}
@@ -0,0 +1,35 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// spanParentContext returns the context the transaction is started from,
+// detached from the cancellation of the request with DetachSpanContext
+func spanParentContext(config SentryConfig, request *http.Request) context.Context {
+	if config.DetachSpanContext {
+		return context.WithoutCancel(request.Context())
+	}
+
+	return request.Context()
+}
+
+// cancelOf takes values from one context and cancellation and deadline from another one
+type cancelOf struct {
+	context.Context
+	values context.Context
+}
+
+func (c cancelOf) Value(key any) any {
+	return c.values.Value(key)
+}
+
+// handlerContext keeps the handler cancelled together with the request, even if the span context is detached
+func handlerContext(config SentryConfig, request *http.Request, spanCtx context.Context) context.Context {
+	if config.DetachSpanContext {
+		return cancelOf{Context: request.Context(), values: spanCtx}
+	}
+
+	return spanCtx
+}
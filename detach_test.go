@@ -0,0 +1,38 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestDetachSpanContext() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{DetachSpanContext: true}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var (
+		span       *sentry.Span
+		handlerErr error
+	)
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		cancel() // client disconnects
+		<-c.Request().Context().Done()
+		handlerErr = c.Request().Context().Err()
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.ErrorIs(handlerErr, context.Canceled)
+	s.NoError(span.Context().Err())
+	s.Equal(sentry.SpanStatusCanceled, span.Status)
+	s.Equal("499", span.Tags["resp.status"])
+	s.Len(s.transport.Events(), 1)
+}
@@ -0,0 +1,135 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var _ wrappingWriter = (*bodyDumper)(nil)
+
+// bodyDumper is a response writer that keeps a copy of the response body.
+// It is installed through exposeOptional, so it only implements http.Flusher and http.Hijacker
+// when the wrapped writer does.
+type bodyDumper struct {
+	http.ResponseWriter
+
+	buf *bytes.Buffer
+
+	// skip disables dumping, the body is only passed through
+	skip bool
+
+	// limit is the max size of the dumped body, 0 means no limit
+	limit int
+	// overflow is set when the body was bigger than limit
+	overflow bool
+
+	// tap is the dumper installed above a foreign writer wrapper, the body is dumped there instead
+	tap *bodyDumper
+}
+
+func newBodyDumper(respWriter http.ResponseWriter, limit int) *bodyDumper {
+	return &bodyDumper{
+		ResponseWriter: respWriter,
+		buf:            new(bytes.Buffer),
+		limit:          limit,
+	}
+}
+
+// dump keeps a copy of the written bytes up to the limit
+func (d *bodyDumper) dump(b []byte) {
+	if d.skip {
+		return
+	}
+
+	if d.limit > 0 && d.buf.Len()+len(b) > d.limit {
+		d.overflow = true
+		b = b[:d.limit-d.buf.Len()]
+	}
+
+	d.buf.Write(b)
+}
+
+func (d *bodyDumper) Write(b []byte) (int, error) {
+	nBytes, err := d.ResponseWriter.Write(b)
+
+	d.dump(b[:nBytes])
+
+	if err != nil {
+		err = fmt.Errorf("error writing response: %w", err)
+	}
+
+	return nBytes, err
+}
+
+// GetResponse returns the dumped response body
+func (d *bodyDumper) GetResponse() string {
+	if d.tap != nil {
+		return d.tap.GetResponse()
+	}
+
+	return d.buf.String()
+}
+
+// Overflow reports whether the body was bigger than the limit
+func (d *bodyDumper) Overflow() bool {
+	if d.tap != nil {
+		return d.tap.Overflow()
+	}
+
+	return d.overflow
+}
+
+// SkipBody stops dumping of the response body
+func (d *bodyDumper) SkipBody() {
+	d.skip = true
+
+	if d.tap != nil {
+		d.tap.SkipBody()
+	}
+}
+
+func (d *bodyDumper) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := d.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+
+	return pusher.Push(target, opts)
+}
+
+func (d *bodyDumper) ReadFrom(r io.Reader) (int64, error) {
+	readerFrom, ok := d.ResponseWriter.(io.ReaderFrom)
+	if !ok {
+		return io.Copy(writerOnly{d}, r)
+	}
+
+	if d.skip {
+		return readerFrom.ReadFrom(r)
+	}
+
+	// tee the source, so the body is dumped while the underlying writer still handles the copy
+	return readerFrom.ReadFrom(io.TeeReader(r, dumpWriter{d}))
+}
+
+// Unwrap returns the original writer, used by http.ResponseController
+func (d *bodyDumper) Unwrap() http.ResponseWriter {
+	return d.ResponseWriter
+}
+
+// writerOnly hides ReadFrom of the dumper to avoid recursion in io.Copy
+type writerOnly struct {
+	io.Writer
+}
+
+// dumpWriter only dumps written bytes
+type dumpWriter struct {
+	d *bodyDumper
+}
+
+func (w dumpWriter) Write(b []byte) (int, error) {
+	w.d.dump(b)
+
+	return len(b), nil
+}
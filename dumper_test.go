@@ -0,0 +1,119 @@
+package echosentrymiddleware
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fullWriterMock struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+	pushed   string
+	readFrom bool
+}
+
+func (w *fullWriterMock) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+func (w *fullWriterMock) Push(target string, _ *http.PushOptions) error {
+	w.pushed = target
+	return nil
+}
+
+func (w *fullWriterMock) ReadFrom(r io.Reader) (int64, error) {
+	w.readFrom = true
+	return w.ResponseRecorder.Body.ReadFrom(r)
+}
+
+func TestBodyDumper(t *testing.T) {
+	t.Run("pass through", func(t *testing.T) {
+		w := &fullWriterMock{ResponseRecorder: httptest.NewRecorder()}
+		d := newBodyDumper(w, 0)
+
+		_, err := d.Write([]byte("test"))
+		require.NoError(t, err)
+
+		rw := exposeOptional(d)
+		require.Implements(t, (*http.Flusher)(nil), rw)
+		require.Implements(t, (*http.Hijacker)(nil), rw)
+
+		rw.(http.Flusher).Flush()
+		require.True(t, w.Flushed)
+
+		_, _, err = rw.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		require.True(t, w.hijacked)
+
+		require.NoError(t, d.Push("/style.css", nil))
+		require.Equal(t, "/style.css", w.pushed)
+
+		n, err := d.ReadFrom(strings.NewReader("Body"))
+		require.NoError(t, err)
+		require.EqualValues(t, 4, n)
+		require.True(t, w.readFrom)
+
+		require.Equal(t, "testBody", w.Body.String())
+		require.Equal(t, "testBody", d.GetResponse())
+		require.Equal(t, w, http.ResponseWriter(d.Unwrap()))
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		d := newBodyDumper(struct{ http.ResponseWriter }{w}, 0)
+
+		rw := exposeOptional(d)
+		_, isFlusher := rw.(http.Flusher)
+		require.False(t, isFlusher)
+		_, isHijacker := rw.(http.Hijacker)
+		require.False(t, isHijacker)
+
+		require.ErrorIs(t, d.Push("/style.css", nil), http.ErrNotSupported)
+
+		n, err := d.ReadFrom(strings.NewReader("test"))
+		require.NoError(t, err)
+		require.EqualValues(t, 4, n)
+		require.Equal(t, "test", w.Body.String())
+		require.Equal(t, "test", d.GetResponse())
+	})
+}
+
+func TestBodyDumperPartialSupport(t *testing.T) {
+	w := httptest.NewRecorder()
+	d := newBodyDumper(struct {
+		http.ResponseWriter
+		http.Flusher
+	}{w, w}, 0)
+
+	rw := exposeOptional(d)
+	_, isHijacker := rw.(http.Hijacker)
+	require.False(t, isHijacker)
+
+	rw.(http.Flusher).Flush()
+	require.True(t, w.Flushed)
+	require.True(t, isOwnWriter(rw))
+}
+
+func TestBodyDumperLimit(t *testing.T) {
+	w := httptest.NewRecorder()
+	d := newBodyDumper(struct{ http.ResponseWriter }{w}, 5)
+
+	_, err := d.Write([]byte("test"))
+	require.NoError(t, err)
+	require.False(t, d.Overflow())
+
+	_, err = d.ReadFrom(strings.NewReader("Body"))
+	require.NoError(t, err)
+	require.True(t, d.Overflow())
+
+	require.Equal(t, "testBody", w.Body.String())
+	require.Equal(t, "testB", d.GetResponse())
+}
@@ -0,0 +1,142 @@
+package echosentrymiddleware
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+var (
+	_ http.Pusher   = (*bodyDumper)(nil)
+	_ io.ReaderFrom = (*bodyDumper)(nil)
+	_ http.Flusher  = flushingDumper{}
+	_ http.Hijacker = hijackingDumper{}
+	_ http.Flusher  = flushingHijackingDumper{}
+	_ http.Hijacker = flushingHijackingDumper{}
+)
+
+// bodyDumper is a response writer that keeps a copy of the response body.
+// It is installed through exposeOptional, so it only implements http.Flusher and http.Hijacker
+// when the wrapped writer does.
+type bodyDumper struct {
+	http.ResponseWriter
+
+	buf *bytes.Buffer
+}
+
+func newBodyDumper(respWriter http.ResponseWriter) *bodyDumper {
+	return &bodyDumper{
+		ResponseWriter: respWriter,
+		buf:            new(bytes.Buffer),
+	}
+}
+
+func (d *bodyDumper) Write(b []byte) (int, error) {
+	nBytes, err := d.ResponseWriter.Write(b)
+	d.buf.Write(b[:nBytes])
+
+	if err != nil {
+		err = fmt.Errorf("error writing response: %w", err)
+	}
+
+	return nBytes, err
+}
+
+// GetResponse returns the dumped response body
+func (d *bodyDumper) GetResponse() string {
+	return d.buf.String()
+}
+
+// exposeOptional returns the dumper implementing http.Flusher and http.Hijacker only when the wrapped writer does,
+// so handlers checking for them, like SSE or websocket ones, are not misled
+func (d *bodyDumper) exposeOptional() http.ResponseWriter {
+	_, isFlusher := d.ResponseWriter.(http.Flusher)
+	_, isHijacker := d.ResponseWriter.(http.Hijacker)
+
+	switch {
+	case isFlusher && isHijacker:
+		return flushingHijackingDumper{d}
+	case isFlusher:
+		return flushingDumper{d}
+	case isHijacker:
+		return hijackingDumper{d}
+	default:
+		return d
+	}
+}
+
+func (d *bodyDumper) flush() {
+	d.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (d *bodyDumper) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := d.ResponseWriter.(http.Hijacker).Hijack()
+	if err != nil {
+		err = fmt.Errorf("error hijacking response: %w", err)
+	}
+
+	return conn, rw, err
+}
+
+// flushingDumper is a dumper in front of an http.Flusher
+type flushingDumper struct {
+	*bodyDumper
+}
+
+func (d flushingDumper) Flush() {
+	d.flush()
+}
+
+// hijackingDumper is a dumper in front of an http.Hijacker
+type hijackingDumper struct {
+	*bodyDumper
+}
+
+func (d hijackingDumper) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return d.hijack()
+}
+
+// flushingHijackingDumper is a dumper in front of an http.Flusher and http.Hijacker
+type flushingHijackingDumper struct {
+	*bodyDumper
+}
+
+func (d flushingHijackingDumper) Flush() {
+	d.flush()
+}
+
+func (d flushingHijackingDumper) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return d.hijack()
+}
+
+func (d *bodyDumper) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := d.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+
+	return pusher.Push(target, opts)
+}
+
+func (d *bodyDumper) ReadFrom(r io.Reader) (int64, error) {
+	readerFrom, ok := d.ResponseWriter.(io.ReaderFrom)
+	if !ok {
+		return io.Copy(writerOnly{d}, r)
+	}
+
+	// tee the source, so the body is dumped while the underlying writer still handles the copy
+	return readerFrom.ReadFrom(io.TeeReader(r, d.buf))
+}
+
+// Unwrap returns the original writer, used by http.ResponseController
+func (d *bodyDumper) Unwrap() http.ResponseWriter {
+	return d.ResponseWriter
+}
+
+// writerOnly hides ReadFrom of the dumper to avoid recursion in io.Copy
+type writerOnly struct {
+	io.Writer
+}
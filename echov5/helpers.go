@@ -0,0 +1,99 @@
+package echosentrymiddleware
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v5"
+)
+
+// statusClientClosedRequest is the nginx-style status for requests aborted by the client
+const statusClientClosedRequest = 499
+
+// limitString truncates the string to size bytes without splitting runes or, approximately,
+// grapheme clusters (combining marks, emoji modifiers and ZWJ sequences)
+func limitString(str string, size int) string {
+	if len(str) <= size {
+		return str
+	}
+
+	cut := size
+	for cut > 0 && !utf8.RuneStart(str[cut]) {
+		cut--
+	}
+
+	for cut > 0 {
+		next, _ := utf8.DecodeRuneInString(str[cut:])
+		prev, width := utf8.DecodeLastRuneInString(str[:cut])
+
+		if !isGraphemeExtend(next) && prev != zeroWidthJoiner {
+			break
+		}
+
+		cut -= width
+	}
+
+	return str[:cut]
+}
+
+const zeroWidthJoiner = '\u200d'
+
+// isGraphemeExtend reports whether the rune continues the preceding grapheme cluster
+func isGraphemeExtend(r rune) bool {
+	switch {
+	case r == zeroWidthJoiner:
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f: // variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // emoji skin tone modifiers
+		return true
+	}
+
+	return unicode.In(r, unicode.Mn, unicode.Me)
+}
+
+func limitStringWithDots(str string, size int) string {
+	if size <= 10 {
+		return limitString(str, size)
+	}
+
+	result := limitString(str, size-3)
+
+	if result == str {
+		return str
+	}
+
+	return result + "..."
+}
+
+func prepareTagValue(str string) string {
+	size := 200 // limit of sentry
+
+	str = strings.ReplaceAll(str, "\n", " ") // no \n in strings
+
+	return limitStringWithDots(str, size)
+}
+
+func prepareTagName(str string) string {
+	return limitString(str, 32)
+}
+
+func setTag(span *sentry.Span, tag, value string) {
+	if tag == "" || value == "" {
+		return
+	}
+
+	span.SetTag(prepareTagName(tag), prepareTagValue(value))
+}
+
+func getRequestID(ctx *echo.Context) string {
+	requestID := ctx.Request().Header.Get(echo.HeaderXRequestID) // request-id generated by reverse-proxy
+	if requestID == "" {
+		// missed request-id from proxy, got generated one by middleware.RequestID()
+		requestID = ctx.Response().Header().Get(echo.HeaderXRequestID)
+	}
+
+	return requestID
+}
@@ -0,0 +1,199 @@
+// Package echosentrymiddleware is a middleware for echo v5 framework that sends traces to Sentry.
+// It has feature parity with the baseline echo v4 middleware (tracing, header and body dumping) only,
+// later features of the echo v4 module are not ported.
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+)
+
+type BodySkipper func(*echo.Context) (skipReqBody bool, skipRespBody bool)
+
+func defaultBodySkipper(*echo.Context) (skipReqBody bool, skipRespBody bool) {
+	return
+}
+
+type (
+	// SentryConfig defines the config for Sentry Performance middleware.
+	SentryConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper middleware.Skipper
+
+		// BodySkipper defines a function to exclude body from logging
+		BodySkipper BodySkipper
+
+		// add req headers & resp headers to tracing tags
+		AreHeadersDump bool
+
+		// add req body & resp body to attributes
+		IsBodyDump bool
+	}
+)
+
+var (
+	// DefaultSentryConfig is the default Sentry Performance middleware config.
+	DefaultSentryConfig = SentryConfig{
+		Skipper:        middleware.DefaultSkipper,
+		AreHeadersDump: true,
+		IsBodyDump:     false,
+	}
+)
+
+// Middleware returns a Sentry middleware with default config
+func Middleware() echo.MiddlewareFunc {
+	return MiddlewareWithConfig(DefaultSentryConfig)
+}
+
+// MiddlewareWithConfig returns a Sentry middleware with config.
+func MiddlewareWithConfig(config SentryConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+
+	if config.BodySkipper == nil {
+		config.BodySkipper = defaultBodySkipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if config.Skipper(c) || c.Request() == nil || c.Response() == nil {
+				return next(c)
+			}
+
+			request, span, endSpan := createSpan(c)
+			defer endSpan()
+
+			ctx := span.Context()
+
+			setTag(span, "client_ip", c.RealIP())
+			setTag(span, "remote_addr", request.RemoteAddr)
+			setTag(span, "request_uri", request.RequestURI)
+			setTag(span, "path", c.Path())
+
+			skipReqBody, skipRespBody := config.BodySkipper(c)
+
+			respDumper := dumpReq(c, config, span, request, skipReqBody)
+
+			// setup request context - add span
+			c.SetRequest(request.WithContext(ctx))
+
+			// call next middleware / controller
+			// echo v5 runs the error handler once the chain has returned, so the error is only recorded here
+			err := next(c)
+			if err != nil {
+				setTag(span, "echo.error", err.Error())
+			}
+
+			dumpResp(c, config, span, respDumper, skipRespBody, err)
+
+			return err
+		}
+	}
+}
+
+func dumpResp(c *echo.Context, config SentryConfig, span *sentry.Span, respDumper *bodyDumper, skipRespBody bool, err error) {
+	setTag(span, "request_id", getRequestID(c))
+
+	status, spanStatus := getResponseStatus(c, err)
+	span.Status = spanStatus
+	setTag(span, "resp.status", strconv.Itoa(status))
+
+	// Dump response headers
+	if config.AreHeadersDump {
+		for k := range c.Response().Header() {
+			setTag(span, "resp.header."+k, c.Response().Header().Get(k))
+		}
+	}
+
+	// Dump response body
+	if config.IsBodyDump {
+		respBody := respDumper.GetResponse()
+
+		if respBody != "" && skipRespBody {
+			respBody = "[excluded]"
+		}
+
+		setTag(span, "resp.body", respBody)
+	}
+}
+
+func dumpReq(c *echo.Context, config SentryConfig, span *sentry.Span, request *http.Request, skipReqBody bool) *bodyDumper {
+	if username, _, ok := request.BasicAuth(); ok {
+		setTag(span, "user", username)
+	}
+
+	// Add path parameters
+	for _, param := range c.PathValues() {
+		setTag(span, "path."+param.Name, param.Value)
+	}
+
+	// Dump request headers
+	if config.AreHeadersDump {
+		for k := range request.Header {
+			setTag(span, "req.header."+k, request.Header.Get(k))
+		}
+	}
+
+	// Dump request & response body
+	var respDumper *bodyDumper
+
+	if config.IsBodyDump {
+		// request
+		if request.Body != nil {
+			reqBody := []byte("[excluded]")
+
+			if !skipReqBody {
+				var err error
+
+				reqBody, err = io.ReadAll(request.Body)
+				if err == nil {
+					_ = request.Body.Close()
+					request.Body = io.NopCloser(bytes.NewBuffer(reqBody)) // reset original request body
+				}
+			}
+
+			setTag(span, "req.body", string(reqBody))
+		}
+
+		// response
+		respDumper = newBodyDumper(c.Response())
+		c.SetResponse(respDumper.exposeOptional())
+	}
+
+	return respDumper
+}
+
+func createSpan(c *echo.Context) (*http.Request, *sentry.Span, func()) {
+	request := c.Request()
+	savedCtx := request.Context()
+	opname := "HTTP " + request.Method + " " + c.Path()
+	tname := "HTTP " + request.Method + " " + c.Request().RequestURI
+	span := sentry.StartSpan(savedCtx, opname, sentry.WithTransactionName(tname))
+
+	return request, span, func() {
+		request = request.WithContext(savedCtx)
+		c.SetRequest(request)
+
+		defer span.Finish()
+	}
+}
+
+func getResponseStatus(c *echo.Context, err error) (int, sentry.SpanStatus) {
+	resp, status := echo.ResolveResponseStatus(c.Response(), err)
+
+	// client went away before anything was written
+	if (resp == nil || !resp.Committed) && errors.Is(c.Request().Context().Err(), context.Canceled) {
+		return statusClientClosedRequest, sentry.SpanStatusCanceled
+	}
+
+	return status, sentry.HTTPtoSpanStatus(status)
+}
@@ -0,0 +1,151 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	contentTypeHeader = "req.header.Content-Type"
+	respStatus        = "resp.status"
+)
+
+var _ sentry.Transport = (*TransportMock)(nil)
+
+type TransportMock struct {
+	lock   sync.Mutex
+	events []*sentry.Event
+}
+
+func (*TransportMock) Configure(_ sentry.ClientOptions) { /* stub */ }
+func (t *TransportMock) SendEvent(event *sentry.Event) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.events = append(t.events, event)
+}
+func (t *TransportMock) Flush(_ time.Duration) bool {
+	clear(t.events)
+	return true
+}
+
+func (*TransportMock) Close() {
+	/* stub */
+}
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+	e *echo.Echo
+}
+
+func (s *MiddlewareTestSuite) SetupTest() {
+	err := sentry.Init(sentry.ClientOptions{
+		EnableTracing: true,
+		Transport:     &TransportMock{},
+	})
+	s.NoError(err)
+	s.e = echo.New()
+}
+
+func (s *MiddlewareTestSuite) TestMiddlewareWithConfig() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		AreHeadersDump: true,
+		IsBodyDump:     true,
+	}))
+
+	s.Run("Test Post", func() {
+		var span *sentry.Span
+		s.e.POST("/:id", func(c *echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			s.NotNil(span)
+			s.NotEmpty(span.Tags["client_ip"])
+			s.Equal("1", span.Tags["path.id"])
+			s.Equal(echo.MIMETextPlain, span.Tags[contentTypeHeader])
+			s.Equal("testBody", span.Tags["req.body"])
+			return c.String(http.StatusOK, "test")
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/1", strings.NewReader("testBody"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		s.Equal(http.StatusOK, rec.Code)
+		s.Equal("test", rec.Body.String())
+		s.Equal(sentry.SpanStatusOK, span.Status)
+		s.Equal(strconv.Itoa(http.StatusOK), span.Tags[respStatus])
+		s.Equal("test", span.Tags["resp.body"])
+	})
+
+	s.Run("Test Error", func() {
+		var span *sentry.Span
+		s.e.GET("/error", func(c *echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return echo.ErrNotFound
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/error", nil)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		s.Equal(http.StatusNotFound, rec.Code)
+		s.Equal(sentry.SpanStatusNotFound, span.Status)
+		s.Equal(strconv.Itoa(http.StatusNotFound), span.Tags[respStatus])
+		s.NotEmpty(span.Tags["echo.error"])
+	})
+
+	s.Run("Test Canceled", func() {
+		var span *sentry.Span
+		s.e.GET("/canceled", func(c *echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req := httptest.NewRequest(http.MethodGet, "/canceled", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		s.Equal(sentry.SpanStatusCanceled, span.Status)
+		s.Equal(strconv.Itoa(statusClientClosedRequest), span.Tags[respStatus])
+	})
+}
+
+func TestGetResponseStatus(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	status, spanStatus := getResponseStatus(c, errors.New("test"))
+	require.Equal(t, http.StatusInternalServerError, status)
+	require.Equal(t, sentry.SpanStatusInternalError, spanStatus)
+}
+
+func TestBodyDumperOptionalInterfaces(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	rw := newBodyDumper(struct{ http.ResponseWriter }{rec}).exposeOptional()
+	_, isFlusher := rw.(http.Flusher)
+	require.False(t, isFlusher)
+	_, isHijacker := rw.(http.Hijacker)
+	require.False(t, isHijacker)
+
+	rw = newBodyDumper(rec).exposeOptional()
+	_, isHijacker = rw.(http.Hijacker)
+	require.False(t, isHijacker)
+	rw.(http.Flusher).Flush()
+	require.True(t, rec.Flushed)
+}
+
+func TestMiddleware(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
@@ -0,0 +1,19 @@
+package echosentrymiddleware
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// dumpEncoding tags Content-Encoding and the charset of Content-Type, even without headers dumping,
+// as they affect the interpretation of dumped bodies and payload sizes
+func dumpEncoding(span *sentry.Span, config SentryConfig, kind string, header http.Header) {
+	setTag(span, config, kind+".content_encoding", header.Get(echo.HeaderContentEncoding))
+
+	if _, params, err := mime.ParseMediaType(header.Get(echo.HeaderContentType)); err == nil {
+		setTag(span, config, kind+".charset", params["charset"])
+	}
+}
@@ -0,0 +1,51 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func (s *MiddlewareTestSuite) TestEncodingTags() {
+	s.e.Use(middleware.GzipWithConfig(middleware.GzipConfig{MinLength: 1}))
+	s.e.Use(MiddlewareWithConfig(SentryConfig{AreHeadersDump: false}))
+
+	var span *sentry.Span
+	s.e.POST("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.String(http.StatusOK, "test")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+	req.Header.Set(echo.HeaderContentType, "text/plain; charset=ISO-8859-1")
+	req.Header.Set(echo.HeaderContentEncoding, "identity")
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Equal("identity", span.Tags["req.content_encoding"])
+	s.Equal("ISO-8859-1", span.Tags["req.charset"])
+	s.Equal("gzip", span.Tags["resp.content_encoding"])
+	s.Equal("UTF-8", span.Tags["resp.charset"])
+	s.NotContains(span.Tags, "req.header.Content-Type")
+}
+
+func (s *MiddlewareTestSuite) TestEncodingTagsMissing() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.NotContains(span.Tags, "req.content_encoding")
+	s.NotContains(span.Tags, "req.charset")
+	s.NotContains(span.Tags, "resp.content_encoding")
+	s.NotContains(span.Tags, "resp.charset")
+}
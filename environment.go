@@ -0,0 +1,25 @@
+package echosentrymiddleware
+
+import (
+	"github.com/getsentry/sentry-go"
+)
+
+// overrideEnvironment forces environment and release of the events sent via the hub,
+// the scope processors run after the client options are applied
+func overrideEnvironment(hub *sentry.Hub, environment, release string) {
+	if environment == "" && release == "" {
+		return
+	}
+
+	hub.Scope().AddEventProcessor(func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		if environment != "" {
+			event.Environment = environment
+		}
+
+		if release != "" {
+			event.Release = release
+		}
+
+		return event
+	})
+}
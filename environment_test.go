@@ -0,0 +1,38 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestEnvironmentOverride() {
+	s.NoError(sentry.Init(sentry.ClientOptions{
+		EnableTracing: true,
+		Transport:     s.transport,
+		Environment:   "production",
+		Release:       "app@1.0.0",
+	}))
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors: true,
+		Environment:   "staging",
+	}))
+	s.e.GET("/", func(echo.Context) error {
+		return echo.ErrInternalServerError
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Len(events, 2) // error and transaction
+
+	for _, event := range events {
+		s.Equal("staging", event.Environment)
+		s.Equal("app@1.0.0", event.Release)
+	}
+}
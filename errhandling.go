@@ -0,0 +1,54 @@
+package echosentrymiddleware
+
+import (
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorHandlingMode defines how errors returned by the handler are passed on
+type ErrorHandlingMode int
+
+const (
+	// ErrorCallAndReturn calls the HTTPErrorHandler via c.Error and returns the error to the outer middleware
+	ErrorCallAndReturn ErrorHandlingMode = iota
+	// ErrorCallAndSwallow calls the HTTPErrorHandler via c.Error and returns nil,
+	// so the error handler runs only once when outer middleware handles errors too
+	ErrorCallAndSwallow
+	// ErrorReturnOnly returns the error without calling the HTTPErrorHandler, leaving it to echo or outer middleware
+	ErrorReturnOnly
+)
+
+// handleError passes the error of the handler on according to the mode and returns the error of the middleware
+func handleError(c echo.Context, mode ErrorHandlingMode, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch mode {
+	case ErrorCallAndSwallow:
+		c.Error(err)
+		return nil
+	case ErrorReturnOnly:
+		return err
+	default:
+		c.Error(err)
+		return err
+	}
+}
+
+// handleErrorInSpan calls handleError with the transaction in the request context, so a custom HTTPErrorHandler
+// finds the request hub and span the way the handler did
+func handleErrorInSpan(c echo.Context, config SentryConfig, span *sentry.Span, timedOut bool, err error) error {
+	// the request of an abandoned handler still carries the span
+	if err == nil || timedOut || config.ErrorHandling == ErrorReturnOnly {
+		return handleError(c, config.ErrorHandling, err)
+	}
+
+	current := c.Request()
+	injected := current.WithContext(handlerContext(config, current, markHandled(span.Context(), config)))
+	c.SetRequest(injected)
+
+	defer restoreRequest(c, current, injected)
+
+	return handleError(c, config.ErrorHandling, err)
+}
@@ -0,0 +1,78 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestErrorHandling() {
+	tests := []struct {
+		mode  ErrorHandlingMode
+		calls int
+	}{
+		{mode: ErrorCallAndReturn, calls: 2},
+		{mode: ErrorCallAndSwallow, calls: 1},
+		{mode: ErrorReturnOnly, calls: 1},
+	}
+
+	for _, tt := range tests {
+		e := echo.New()
+		calls := 0
+		e.HTTPErrorHandler = func(err error, c echo.Context) {
+			calls++
+			e.DefaultHTTPErrorHandler(err, c)
+		}
+
+		e.Use(MiddlewareWithConfig(SentryConfig{ErrorHandling: tt.mode}))
+
+		var span *sentry.Span
+		e.GET("/", func(c echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return echo.NewHTTPError(http.StatusTeapot, errors.New("test error"))
+		})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		s.Equal(tt.calls, calls)
+		s.Equal(http.StatusTeapot, rec.Code)
+		s.Equal("418", span.Tags["resp.status"])
+	}
+}
+
+func (s *MiddlewareTestSuite) TestCaptureFromErrorHandler() {
+	var (
+		span, handlerSpan *sentry.Span
+		eventID           *sentry.EventID
+	)
+
+	s.e.HTTPErrorHandler = func(err error, c echo.Context) {
+		if eventID == nil {
+			handlerSpan = sentry.SpanFromContext(c.Request().Context())
+			eventID = CaptureError(c, err)
+		}
+
+		s.e.DefaultHTTPErrorHandler(err, c)
+	}
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{}))
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return errors.New("test error")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Require().NotNil(eventID)
+	s.Same(span, handlerSpan)
+
+	events := s.errorEvents()
+	s.Require().Len(events, 1)
+	s.Equal(span.TraceID, events[0].Contexts["trace"]["trace_id"])
+}
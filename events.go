@@ -0,0 +1,78 @@
+package echosentrymiddleware
+
+import (
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// CaptureOption customizes the scope of a single event captured with CaptureError or CaptureMessage
+type CaptureOption func(scope *sentry.Scope)
+
+// WithTag sets a tag on the captured event
+func WithTag(key, value string) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetTag(key, value)
+	}
+}
+
+// WithExtra sets an extra on the captured event
+func WithExtra(key string, value interface{}) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetExtra(key, value)
+	}
+}
+
+// WithLevel sets the level of the captured event
+func WithLevel(level sentry.Level) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetLevel(level)
+	}
+}
+
+// CaptureError captures the error on the request scoped hub, so the event is linked to the transaction
+// and carries the request and route tags. It returns nil when the request is not handled by the middleware.
+func CaptureError(c echo.Context, err error, opts ...CaptureOption) *sentry.EventID {
+	if err == nil {
+		return nil
+	}
+
+	return captureOnRequestHub(c, opts, func(hub *sentry.Hub) *sentry.EventID {
+		return hub.CaptureException(err)
+	})
+}
+
+// CaptureMessage captures the message on the request scoped hub, like CaptureError
+func CaptureMessage(c echo.Context, message string, opts ...CaptureOption) *sentry.EventID {
+	return captureOnRequestHub(c, opts, func(hub *sentry.Hub) *sentry.EventID {
+		return hub.CaptureMessage(message)
+	})
+}
+
+// AddBreadcrumb adds the breadcrumb to the request scoped hub,
+// it is attached to the events captured later during the request
+func AddBreadcrumb(c echo.Context, breadcrumb *sentry.Breadcrumb) {
+	if hub := sentry.GetHubFromContext(c.Request().Context()); hub != nil {
+		hub.AddBreadcrumb(breadcrumb, nil)
+	}
+}
+
+func captureOnRequestHub(c echo.Context, opts []CaptureOption, capture func(hub *sentry.Hub) *sentry.EventID) *sentry.EventID {
+	hub := sentry.GetHubFromContext(c.Request().Context())
+	if hub == nil {
+		return nil
+	}
+
+	var eventID *sentry.EventID
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("path", c.Path())
+
+		for _, opt := range opts {
+			opt(scope)
+		}
+
+		eventID = capture(hub)
+	})
+
+	return eventID
+}
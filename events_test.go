@@ -0,0 +1,60 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestCaptureError() {
+	var span *sentry.Span
+
+	s.e.Use(Middleware())
+	s.e.GET("/users/:id", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		s.NotNil(CaptureError(c, errors.New("test error"), WithTag("component", "users"), WithLevel(sentry.LevelWarning)))
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	events := s.errorEvents()
+	s.Len(events, 1)
+	s.Equal("/users/:id", events[0].Tags["path"])
+	s.Equal("users", events[0].Tags["component"])
+	s.Equal(sentry.LevelWarning, events[0].Level)
+	s.True(strings.HasSuffix(events[0].Request.URL, "/users/42"))
+	s.Equal(span.TraceID, events[0].Contexts["trace"]["trace_id"])
+
+	s.Run("not handled by the middleware", func() {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		s.Nil(CaptureError(c, errors.New("test error")))
+		s.Nil(CaptureMessage(c, "test message"))
+		AddBreadcrumb(c, &sentry.Breadcrumb{Message: "test"})
+	})
+}
+
+func (s *MiddlewareTestSuite) TestCaptureMessage() {
+	s.e.Use(Middleware())
+	s.e.GET("/", func(c echo.Context) error {
+		AddBreadcrumb(c, &sentry.Breadcrumb{Category: "cache", Message: "cache miss"})
+		s.NotNil(CaptureMessage(c, "slow cache", WithExtra("key", "users")))
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	events := s.errorEvents()
+	s.Len(events, 1)
+	s.Equal("slow cache", events[0].Message)
+	s.Equal("/", events[0].Tags["path"])
+	s.Equal("users", events[0].Extra["key"])
+	s.Len(events[0].Breadcrumbs, 1)
+	s.Equal("cache miss", events[0].Breadcrumbs[0].Message)
+}
@@ -0,0 +1,71 @@
+package echosentrymiddleware
+
+import (
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// ExperimentsFn returns experiment assignments of the request, experiment name to variant
+type ExperimentsFn func(c echo.Context) map[string]string
+
+// ExperimentsFromHeader reads assignments from a header in "name=variant" pairs separated by commas or semicolons,
+// e.g. "X-Experiments: checkout=B; pricing=control"
+func ExperimentsFromHeader(header string) ExperimentsFn {
+	return func(c echo.Context) map[string]string {
+		experiments := make(map[string]string)
+
+		for _, value := range c.Request().Header.Values(header) {
+			for _, pair := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+				name, variant, ok := strings.Cut(pair, "=")
+				if !ok {
+					continue
+				}
+
+				experiments[strings.TrimSpace(name)] = strings.TrimSpace(variant)
+			}
+		}
+
+		return experiments
+	}
+}
+
+// ExperimentsFromCookies reads assignments from cookies named with the prefix, the rest of the name is the experiment,
+// e.g. cookie "exp_checkout=B" with the prefix "exp_"
+func ExperimentsFromCookies(prefix string) ExperimentsFn {
+	return func(c echo.Context) map[string]string {
+		experiments := make(map[string]string)
+
+		for _, cookie := range c.Cookies() {
+			if name, ok := strings.CutPrefix(cookie.Name, prefix); ok && name != "" {
+				experiments[name] = cookie.Value
+			}
+		}
+
+		return experiments
+	}
+}
+
+// dumpExperiments tags experiment variants on the span and the request scope,
+// so error rates and latencies can be compared between variants
+func dumpExperiments(c echo.Context, config SentryConfig, span *sentry.Span) {
+	if config.ExperimentsFn == nil {
+		return
+	}
+
+	hub := sentry.GetHubFromContext(span.Context())
+
+	for name, variant := range config.ExperimentsFn(c) {
+		if name == "" || variant == "" {
+			continue
+		}
+
+		tag := config.Sanitizer.TagName("experiment." + name)
+		setTag(span, config, tag, variant)
+
+		if hub != nil {
+			hub.Scope().SetTag(tag, config.Sanitizer.TagValue(variant))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestExperiments() {
+	s.Run("header", func() {
+		var span *sentry.Span
+
+		e := echo.New()
+		e.Use(MiddlewareWithConfig(SentryConfig{
+			ExperimentsFn: ExperimentsFromHeader("X-Experiments"),
+		}))
+		e.GET("/", func(c echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Experiments", "checkout=B; pricing=control, broken")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		s.NotNil(span)
+		s.Equal("B", span.Tags["experiment.checkout"])
+		s.Equal("control", span.Tags["experiment.pricing"])
+		s.NotContains(span.Tags, "experiment.broken")
+	})
+
+	s.Run("cookies", func() {
+		s.transport.events = nil
+
+		e := echo.New()
+		e.Use(MiddlewareWithConfig(SentryConfig{
+			CaptureErrors: true,
+			ExperimentsFn: ExperimentsFromCookies("exp_"),
+		}))
+		e.GET("/", func(echo.Context) error {
+			return echo.ErrInternalServerError
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "exp_checkout", Value: "A"})
+		req.AddCookie(&http.Cookie{Name: "session", Value: "secret"})
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		events := s.errorEvents()
+		s.Len(events, 1)
+		s.Equal("A", events[0].Tags["experiment.checkout"])
+		s.NotContains(events[0].Tags, "experiment.session")
+	})
+}
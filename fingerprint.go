@@ -0,0 +1,22 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// getRequestFingerprint returns a stable hash of the request shape: method, route and sorted query keys
+func getRequestFingerprint(request *http.Request, route string) string {
+	query := request.URL.Query()
+	keys := make([]string, 0, len(query))
+
+	for key := range query {
+		keys = append(keys, strings.ToLower(key))
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	return hashValue(request.Method + " " + route + "?" + strings.Join(keys, "&"))
+}
@@ -0,0 +1,18 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRequestFingerprint(t *testing.T) {
+	fingerprint := getRequestFingerprint(httptest.NewRequest(http.MethodGet, "/users/1?b=1&a=2", nil), "/users/:id")
+
+	require.Len(t, fingerprint, 8)
+	require.Equal(t, fingerprint, getRequestFingerprint(httptest.NewRequest(http.MethodGet, "/users/2?A=3&b=4&b=5", nil), "/users/:id"))
+	require.NotEqual(t, fingerprint, getRequestFingerprint(httptest.NewRequest(http.MethodGet, "/users/1?a=2", nil), "/users/:id"))
+	require.NotEqual(t, fingerprint, getRequestFingerprint(httptest.NewRequest(http.MethodPost, "/users/1?b=1&a=2", nil), "/users/:id"))
+}
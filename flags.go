@@ -0,0 +1,42 @@
+package echosentrymiddleware
+
+import (
+	"sort"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// FlagsFn returns the feature flags evaluated for the request, flag name to its value
+type FlagsFn func(c echo.Context) map[string]any
+
+// dumpFlags records feature flags as span data and as the flags context of the request scope,
+// so performance and errors can be sliced by flag state
+func dumpFlags(c echo.Context, config SentryConfig, span *sentry.Span) {
+	if config.FlagsFn == nil {
+		return
+	}
+
+	flags := config.FlagsFn(c)
+	if len(flags) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	values := make([]map[string]any, 0, len(flags))
+
+	for _, name := range names {
+		span.SetData("flag.evaluation."+name, flags[name])
+		values = append(values, map[string]any{"flag": name, "result": flags[name]})
+	}
+
+	if hub := sentry.GetHubFromContext(span.Context()); hub != nil {
+		hub.Scope().SetContext("flags", sentry.Context{"values": values})
+	}
+}
@@ -0,0 +1,37 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestFlags() {
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors: true,
+		FlagsFn: func(echo.Context) map[string]any {
+			return map[string]any{"new-checkout": true, "theme": "dark"}
+		},
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return echo.ErrInternalServerError
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.NotNil(span)
+	s.Equal(true, span.Data["flag.evaluation.new-checkout"])
+	s.Equal("dark", span.Data["flag.evaluation.theme"])
+
+	events := s.errorEvents()
+	s.Len(events, 1)
+	s.Equal(sentry.Context{"values": []map[string]any{
+		{"flag": "new-checkout", "result": true},
+		{"flag": "theme", "result": "dark"},
+	}}, events[0].Contexts["flags"])
+}
@@ -0,0 +1,40 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// isOwnWriter reports whether the writer was installed by the middleware
+func isOwnWriter(w http.ResponseWriter) bool {
+	switch unexposeOptional(w).(type) {
+	case *bodyDumper, *timeoutDetector:
+		return true
+	}
+
+	return false
+}
+
+// tapForeignWriter moves dumping of the response body above writer wrappers installed by middleware
+// registered after this one (e.g. compression), so the body is dumped as written by the handler.
+// The hook runs when the headers are written, after all wrappers are in place regardless of the order.
+func tapForeignWriter(c echo.Context, span *sentry.Span, d *bodyDumper) {
+	resp := c.Response()
+	resp.Before(func() {
+		if isOwnWriter(resp.Writer) {
+			return
+		}
+
+		tap := newBodyDumper(resp.Writer, d.limit)
+		tap.skip = d.skip
+		resp.Writer = exposeOptional(tap)
+
+		// the wrapped dumper only sees bytes transformed by the foreign writer
+		d.skip = true
+		d.tap = tap
+
+		span.SetData("resp.writer.foreign", true)
+	})
+}
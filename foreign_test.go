@@ -0,0 +1,84 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func (s *MiddlewareTestSuite) TestForeignWriterAfterMiddleware() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+	s.e.Use(middleware.GzipWithConfig(middleware.GzipConfig{MinLength: 1}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.String(http.StatusOK, "plain body")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+
+	reader, err := gzip.NewReader(rec.Body)
+	s.Require().NoError(err)
+	body, err := io.ReadAll(reader)
+	s.Require().NoError(err)
+
+	s.Equal("plain body", string(body))
+	s.Equal("plain body", span.Tags["resp.body"])
+	s.Equal(true, span.Data["resp.writer.foreign"])
+}
+
+func (s *MiddlewareTestSuite) TestForeignWriterBeforeMiddleware() {
+	s.e.Use(middleware.GzipWithConfig(middleware.GzipConfig{MinLength: 1}))
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.String(http.StatusOK, "plain body")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Equal("plain body", span.Tags["resp.body"])
+	s.NotContains(span.Data, "resp.writer.foreign")
+}
+
+type sinkWriter struct {
+	http.ResponseWriter
+	sink bytes.Buffer
+}
+
+func (w *sinkWriter) Write(b []byte) (int, error) {
+	return w.sink.Write(b)
+}
+
+func (s *MiddlewareTestSuite) TestForeignWriterCaptureUnavailable() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		c.Response().WriteHeader(http.StatusOK)
+		// the writer is replaced after the headers, so the body never reaches the dumpers
+		c.Response().Writer = &sinkWriter{ResponseWriter: c.Response().Writer}
+		_, err := c.Response().Write([]byte("buffered"))
+		return err
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Equal("unavailable", span.Tags["resp.body.capture"])
+	s.Empty(span.Tags["resp.body"])
+}
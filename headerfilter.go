@@ -0,0 +1,58 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HeaderFilter selects dumped headers by name (case-insensitive), a trailing "*" matches a prefix,
+// e.g. "X-RateLimit-*"
+type HeaderFilter struct {
+	// Allow defines dumped headers, empty means all
+	Allow []string
+	// Deny defines headers never dumped, it takes precedence over Allow
+	Deny []string
+}
+
+// apply returns the names passing the filter, a nil filter passes all of them
+func (f *HeaderFilter) apply(names []string) []string {
+	if f == nil {
+		return names
+	}
+
+	filtered := names[:0]
+
+	for _, name := range names {
+		if matchHeader(name, f.Deny) {
+			continue
+		}
+
+		if len(f.Allow) > 0 && !matchHeader(name, f.Allow) {
+			continue
+		}
+
+		filtered = append(filtered, name)
+	}
+
+	return filtered
+}
+
+func matchHeader(name string, patterns []string) bool {
+	name = http.CanonicalHeaderKey(name)
+
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, http.CanonicalHeaderKey(prefix)) {
+				return true
+			}
+
+			continue
+		}
+
+		if name == http.CanonicalHeaderKey(pattern) {
+			return true
+		}
+	}
+
+	return false
+}
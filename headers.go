@@ -0,0 +1,87 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// dumpHeaders records headers as "<kind>.header.*" tags, or as one data entry with HeadersAsData.
+// Response headers are filtered with RespHeaders, which enables them regardless of AreHeadersDump.
+// Credentials are masked. Only MaxHeaderTags headers (in name order) are recorded, the number of dropped ones goes to "<kind>.headers.dropped".
+func dumpHeaders(span *sentry.Span, config SentryConfig, kind, dataKey string, header http.Header) {
+	enabled, filter := config.AreHeadersDump, (*HeaderFilter)(nil)
+	if kind == "resp" && config.RespHeaders != nil {
+		enabled, filter = true, config.RespHeaders
+	}
+
+	if !enabled {
+		return
+	}
+
+	names := headerNames(header)
+
+	if filter != nil {
+		for _, name := range names {
+			if matchHeader(name, filter.Deny) {
+				auditRedaction(span, kind+".header", name)
+			}
+		}
+	}
+
+	names = limitNames(span, kind+".headers.dropped", config.MaxHeaderTags, filter.apply(names))
+
+	if config.HeadersAsData {
+		data := make(map[string]string, len(names))
+		for _, k := range names {
+			data[k] = dumpHeaderValue(span, config, kind, k, header.Get(k))
+		}
+
+		span.SetData(dataKey, data)
+
+		return
+	}
+
+	for _, k := range names {
+		setTag(span, config, kind+".header."+k, dumpHeaderValue(span, config, kind, k, header.Get(k)))
+	}
+}
+
+func headerNames(header http.Header) []string {
+	names := make([]string, 0, len(header))
+	for k := range header {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// limitNames keeps the first limit names and records the number of dropped ones, 0 means no limit
+func limitNames(span *sentry.Span, droppedKey string, limit int, names []string) []string {
+	if limit <= 0 || len(names) <= limit {
+		return names
+	}
+
+	span.SetData(droppedKey, len(names)-limit)
+
+	return names[:limit]
+}
+
+// dumpHeaderValue masks credentials (and PII with DetectPII) and audits the masking
+func dumpHeaderValue(span *sentry.Span, config SentryConfig, kind, name, value string) string {
+	if isDebugSampleHeader(config, name) || isAPIKeyHeader(config, name) {
+		auditRedaction(span, kind+".header", name)
+		return scrubbedValue
+	}
+
+	masked := maskCredentials(name, value)
+	if masked != value {
+		auditRedaction(span, kind+".header", name)
+		return masked
+	}
+
+	return detectPII(span, config, kind+".header", escapeInvalidUTF8(value))
+}
@@ -0,0 +1,94 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestHeadersAsData() {
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		AreHeadersDump: true,
+		HeadersAsData:  true,
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		c.Response().Header().Set("X-Resp", "resp")
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Req", "req")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.NotNil(span)
+	s.Equal(map[string]string{"X-Req": "req"}, span.Data["http.request.headers"])
+	s.Equal(map[string]string{"X-Resp": "resp"}, span.Data["http.response.headers"])
+	s.NotContains(span.Tags, "req.header.X-Req")
+	s.NotContains(span.Tags, "resp.header.X-Resp")
+}
+
+func (s *MiddlewareTestSuite) TestMaxTags() {
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		AreHeadersDump: true,
+		MaxHeaderTags:  2,
+		MaxParamTags:   1,
+	}))
+	s.e.GET("/:a/:b/:c", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/1/2/3", nil)
+	req.Header.Set("X-A", "a")
+	req.Header.Set("X-B", "b")
+	req.Header.Set("X-C", "c")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.NotNil(span)
+	s.Equal("a", span.Tags["req.header.X-A"])
+	s.Equal("b", span.Tags["req.header.X-B"])
+	s.NotContains(span.Tags, "req.header.X-C")
+	s.Equal(1, span.Data["req.headers.dropped"])
+
+	s.Equal("1", span.Tags["path.a"])
+	s.NotContains(span.Tags, "path.b")
+	s.Equal(2, span.Data["path.dropped"])
+}
+
+func (s *MiddlewareTestSuite) TestRespHeadersFilter() {
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		RespHeaders: &HeaderFilter{
+			Allow: []string{"cache-control", "X-RateLimit-*"},
+			Deny:  []string{"X-RateLimit-Policy"},
+		},
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		c.Response().Header().Set("Cache-Control", "no-cache")
+		c.Response().Header().Set("X-RateLimit-Limit", "10")
+		c.Response().Header().Set("X-RateLimit-Policy", "10;w=1")
+		c.Response().Header().Set("X-Other", "other")
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Req", "req")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.NotNil(span)
+	s.Equal("no-cache", span.Tags["resp.header.Cache-Control"])
+	s.Equal("10", span.Tags["resp.header.X-Ratelimit-Limit"])
+	s.NotContains(span.Tags, "resp.header.X-Ratelimit-Policy")
+	s.NotContains(span.Tags, "resp.header.X-Other")
+	s.NotContains(span.Tags, "req.header.X-Req")
+}
@@ -0,0 +1,99 @@
+package echosentrymiddleware
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultMaxBreadcrumbs and maxBreadcrumbs are the limits applied by sentry-go
+	defaultMaxBreadcrumbs = 30
+	maxBreadcrumbs        = 100
+)
+
+// startHeartbeat adds a breadcrumb with the elapsed time and bytes written so far every HeartbeatInterval
+// while the handler runs, so events of long requests show their progress. It returns the function stopping it.
+func startHeartbeat(c echo.Context, config SentryConfig, span *sentry.Span) (stop func()) {
+	if config.HeartbeatInterval <= 0 {
+		return func() {}
+	}
+
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil {
+		return func() {}
+	}
+
+	// the handler span pushes a scope cloned from this one, events captured after the handler only see this one
+	scope := hub.Scope()
+
+	// the size is only read by the writing goroutine, the heartbeat gets a copy
+	var written atomic.Int64
+
+	resp := c.Response()
+	resp.After(func() {
+		written.Store(resp.Size)
+	})
+
+	ticker := time.NewTicker(config.HeartbeatInterval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				addHeartbeat(hub, scope, &sentry.Breadcrumb{
+					Category: "request.heartbeat",
+					Message:  "request in progress",
+					Data: map[string]interface{}{
+						"elapsed_ms":    config.Clock().Sub(span.StartTime).Milliseconds(),
+						"bytes_written": written.Load(),
+					},
+					Level: sentry.LevelInfo,
+				})
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
+
+// addHeartbeat adds the breadcrumb to the request scope and to the current scope of the handler,
+// following the breadcrumb options of the client like hub.AddBreadcrumb
+func addHeartbeat(hub *sentry.Hub, scope *sentry.Scope, breadcrumb *sentry.Breadcrumb) {
+	limit := defaultMaxBreadcrumbs
+
+	if client := hub.Client(); client != nil {
+		options := client.Options()
+
+		switch {
+		case options.MaxBreadcrumbs < 0:
+			return
+		case options.MaxBreadcrumbs > 0:
+			limit = min(options.MaxBreadcrumbs, maxBreadcrumbs)
+		}
+
+		if options.BeforeBreadcrumb != nil {
+			if breadcrumb = options.BeforeBreadcrumb(breadcrumb, &sentry.BreadcrumbHint{}); breadcrumb == nil {
+				return
+			}
+		}
+	}
+
+	scope.AddBreadcrumb(breadcrumb, limit)
+
+	if current := hub.Scope(); current != scope {
+		current.AddBreadcrumb(breadcrumb, limit)
+	}
+}
@@ -0,0 +1,61 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestHeartbeat() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors:     true,
+		HeartbeatInterval: 5 * time.Millisecond,
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		if _, err := c.Response().Write([]byte("partial")); err != nil {
+			return err
+		}
+
+		time.Sleep(30 * time.Millisecond)
+
+		return errors.New("test error")
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	events := s.errorEvents()
+	s.Require().Len(events, 1)
+
+	var heartbeats int
+	for _, breadcrumb := range events[0].Breadcrumbs {
+		if breadcrumb.Category != "request.heartbeat" {
+			continue
+		}
+
+		heartbeats++
+		s.Equal(int64(7), breadcrumb.Data["bytes_written"])
+		s.Positive(breadcrumb.Data["elapsed_ms"])
+	}
+
+	s.GreaterOrEqual(heartbeats, 2)
+}
+
+func (s *MiddlewareTestSuite) TestHeartbeatDisabled() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{CaptureErrors: true}))
+	s.e.GET("/", func(echo.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return errors.New("test error")
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	events := s.errorEvents()
+	s.Require().Len(events, 1)
+
+	for _, breadcrumb := range events[0].Breadcrumbs {
+		s.NotEqual("request.heartbeat", breadcrumb.Category)
+	}
+}
@@ -1,30 +1,59 @@
 package echosentrymiddleware
 
 import (
-	"strings"
+	"context"
+	"errors"
+	"net/http"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/labstack/echo/v4"
 )
 
+// statusClientClosedRequest is the nginx-style status for requests aborted by the client
+const statusClientClosedRequest = 499
+
+// limitString truncates the string to size bytes without splitting runes or, approximately,
+// grapheme clusters (combining marks, emoji modifiers and ZWJ sequences)
 func limitString(str string, size int) string {
 	if len(str) <= size {
 		return str
 	}
 
-	bytes := []byte(str)
+	cut := size
+	for cut > 0 && !utf8.RuneStart(str[cut]) {
+		cut--
+	}
+
+	for cut > 0 {
+		next, _ := utf8.DecodeRuneInString(str[cut:])
+		prev, width := utf8.DecodeLastRuneInString(str[:cut])
 
-	if len(bytes) <= size {
-		return str
+		if !isGraphemeExtend(next) && prev != zeroWidthJoiner {
+			break
+		}
+
+		cut -= width
 	}
 
-	validBytes := bytes[:size]
-	for !utf8.Valid(validBytes) {
-		validBytes = validBytes[:len(validBytes)-1]
+	return str[:cut]
+}
+
+const zeroWidthJoiner = '\u200d'
+
+// isGraphemeExtend reports whether the rune continues the preceding grapheme cluster
+func isGraphemeExtend(r rune) bool {
+	switch {
+	case r == zeroWidthJoiner:
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f: // variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // emoji skin tone modifiers
+		return true
 	}
 
-	return string(validBytes)
+	return unicode.In(r, unicode.Mn, unicode.Me)
 }
 
 func limitStringWithDots(str string, size int) string {
@@ -41,24 +70,24 @@ func limitStringWithDots(str string, size int) string {
 	return result + "..."
 }
 
-func prepareTagValue(str string) string {
-	size := 200 // limit of sentry
-
-	str = strings.ReplaceAll(str, "\n", " ") // no \n in strings
-
-	return limitStringWithDots(str, size)
-}
+func setTag(span *sentry.Span, config SentryConfig, tag, value string) {
+	if tag == "" || value == "" {
+		return
+	}
 
-func prepareTagName(str string) string {
-	return limitString(str, 32)
+	span.SetTag(config.Sanitizer.TagName(tag), config.Sanitizer.TagValue(value))
 }
 
-func setTag(span *sentry.Span, tag, value string) {
-	if tag == "" || value == "" {
-		return
+// getHeadersSummary returns number of header values and their size in wire format
+func getHeadersSummary(header http.Header) (count int, size int) {
+	for name, values := range header {
+		for _, value := range values {
+			count++
+			size += len(name) + len(value) + 4 // ": " and CRLF
+		}
 	}
 
-	span.SetTag(prepareTagName(tag), prepareTagValue(value))
+	return count, size
 }
 
 func getRequestID(ctx echo.Context) string {
@@ -70,3 +99,33 @@ func getRequestID(ctx echo.Context) string {
 
 	return requestID
 }
+
+func getResponseStatus(ctx echo.Context, err error) (int, sentry.SpanStatus) {
+	if !ctx.Response().Committed {
+		// client went away before anything was written, echo still reports 200
+		if errors.Is(ctx.Request().Context().Err(), context.Canceled) {
+			return statusClientClosedRequest, sentry.SpanStatusCanceled
+		}
+
+		// error handler did not write the response, so the status comes from the error
+		if err != nil {
+			status := getErrorStatus(err)
+
+			return status, getErrorSpanStatus(err, status)
+		}
+	}
+
+	status := ctx.Response().Status
+
+	return status, sentry.HTTPtoSpanStatus(status)
+}
+
+// getErrorStatus returns the status code of echo.HTTPError or 500 for other errors
+func getErrorStatus(err error) int {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
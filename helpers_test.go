@@ -1,60 +1,40 @@
 package echosentrymiddleware
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/getsentry/sentry-go"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/stretchr/testify/require"
 )
 
-func TestLimitTagValue(t *testing.T) {
+func TestLimitString(t *testing.T) {
 	tests := []struct {
 		name string
 		str  string
+		size int
 		want string
 	}{
-		{
-			name: "Short string",
-			str:  "i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7",
-			want: "i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7",
-		},
-		{
-			name: "Long string containing \\n",
-			str:  "05Kj7z2AXCl603gMJu6B23z2sD05\nKj7z2AXCl603gMJu6B23z2sD05Kj7z\n2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD",
-			want: "05Kj7z2AXCl603gMJu6B23z2sD05 Kj7z2AXCl603gMJu6B23z2sD05Kj7z 2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl60...",
-		},
+		{name: "ascii", str: "abcdef", size: 3, want: "abc"},
+		{name: "short", str: "abc", size: 3, want: "abc"},
+		{name: "multi-byte rune", str: "aéb", size: 2, want: "a"},
+		{name: "emoji", str: "a😀", size: 4, want: "a"},
+		{name: "combining mark", str: "ae\u0301b", size: 3, want: "a"},
+		{name: "skin tone", str: "a👍🏽", size: 6, want: "a"},
+		{name: "zwj sequence", str: "a👩\u200d💻", size: 8, want: "a"},
+		{name: "variation selector", str: "a❤\ufe0f", size: 4, want: "a"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			require.True(t, len(prepareTagValue(tt.str)) <= 200)
-			require.Equal(t, tt.want, prepareTagValue(tt.str))
-		})
-	}
-}
-
-func TestLimitTagName(t *testing.T) {
-	tests := []struct {
-		name string
-		str  string
-		want string
-	}{
-		{
-			name: "Short string",
-			str:  "i3WEG3605Kj7",
-			want: "i3WEG3605Kj7",
-		},
-		{
-			name: "Long string",
-			str:  "05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AX",
-			want: "05Kj7z2AXCl603gMJu6B23z2sD05Kj7z",
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			require.True(t, len(prepareTagName(tt.str)) <= 32)
-			require.Equal(t, tt.want, prepareTagName(tt.str))
+			got := limitString(tt.str, tt.size)
+			require.Equal(t, tt.want, got)
+			require.True(t, utf8.ValidString(got))
 		})
 	}
 }
@@ -80,3 +60,48 @@ func TestGetRequestID(t *testing.T) {
 		require.Equal(t, 32, len(getRequestID(c)))
 	})
 }
+
+func TestGetHeadersSummary(t *testing.T) {
+	header := http.Header{}
+	header.Set("A", "1")
+	header.Add("Bb", "22")
+	header.Add("Bb", "333")
+
+	count, size := getHeadersSummary(header)
+	require.Equal(t, 3, count)
+	require.Equal(t, 6+8+9, size)
+}
+
+func TestGetResponseStatus(t *testing.T) {
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		spanStatus sentry.SpanStatus
+	}{
+		{"http error", echo.NewHTTPError(http.StatusConflict), http.StatusConflict, sentry.SpanStatusAlreadyExists},
+		{"wrapped http error", fmt.Errorf("wrapped: %w", echo.ErrNotFound), http.StatusNotFound, sentry.SpanStatusNotFound},
+		{"generic error", errors.New("test"), http.StatusInternalServerError, sentry.SpanStatusInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+			status, spanStatus := getResponseStatus(c, tt.err)
+			require.Equal(t, tt.status, status)
+			require.Equal(t, tt.spanStatus, spanStatus)
+		})
+	}
+
+	t.Run("committed response", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		c.Response().WriteHeader(http.StatusAccepted)
+
+		status, spanStatus := getResponseStatus(c, errors.New("test"))
+		require.Equal(t, http.StatusAccepted, status)
+		require.Equal(t, sentry.SpanStatusOK, spanStatus)
+	})
+}
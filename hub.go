@@ -0,0 +1,26 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// getHub returns the hub of the request, or a clone of the current hub when there is none
+func getHub(request *http.Request) *sentry.Hub {
+	if hub := sentry.GetHubFromContext(request.Context()); hub != nil {
+		return hub
+	}
+
+	return sentry.CurrentHub().Clone()
+}
+
+// isClientBound reports whether events of the request would be sent anywhere.
+// Without a client (sentry.Init was never called) all the dumping work would be discarded.
+func isClientBound(request *http.Request) bool {
+	if hub := sentry.GetHubFromContext(request.Context()); hub != nil {
+		return hub.Client() != nil
+	}
+
+	return sentry.CurrentHub().Client() != nil
+}
@@ -0,0 +1,35 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestNoClientBound() {
+	var bodySkipperCalled bool
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IsBodyDump: true,
+		BodySkipper: func(echo.Context) (bool, bool) {
+			bodySkipperCalled = true
+			return false, false
+		},
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		s.Nil(sentry.TransactionFromContext(c.Request().Context()))
+		return c.String(http.StatusOK, "test")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(sentry.SetHubOnContext(req.Context(), sentry.NewHub(nil, sentry.NewScope())))
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("test", rec.Body.String())
+	s.False(bodySkipperCalled)
+	s.Empty(s.transport.Events())
+}
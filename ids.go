@@ -0,0 +1,53 @@
+package echosentrymiddleware
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// IDGenerator supplies trace and span IDs of the spans created by the middleware,
+// e.g. to keep golden files of emitted transactions stable in tests. Continued traces keep their trace ID.
+type IDGenerator interface {
+	TraceID() sentry.TraceID
+	SpanID() sentry.SpanID
+}
+
+// SequentialIDGenerator generates IDs from a counter, it is meant for tests only
+type SequentialIDGenerator struct {
+	counter atomic.Uint64
+}
+
+// TraceID returns the next trace ID
+func (g *SequentialIDGenerator) TraceID() sentry.TraceID {
+	var id sentry.TraceID
+
+	binary.BigEndian.PutUint64(id[8:], g.counter.Add(1))
+
+	return id
+}
+
+// SpanID returns the next span ID
+func (g *SequentialIDGenerator) SpanID() sentry.SpanID {
+	var id sentry.SpanID
+
+	binary.BigEndian.PutUint64(id[:], g.counter.Add(1))
+
+	return id
+}
+
+// idOption replaces random IDs of the span with generated ones, the trace ID is only set on roots
+func idOption(generator IDGenerator) sentry.SpanOption {
+	return func(span *sentry.Span) {
+		if generator == nil {
+			return
+		}
+
+		if span.ParentSpanID == (sentry.SpanID{}) {
+			span.TraceID = generator.TraceID()
+		}
+
+		span.SpanID = generator.SpanID()
+	}
+}
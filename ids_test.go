@@ -0,0 +1,79 @@
+package echosentrymiddleware
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestIDGenerator() {
+	s.NoError(sentry.Init(sentry.ClientOptions{
+		EnableTracing:    true,
+		TracesSampleRate: 1,
+		Transport:        s.transport,
+	}))
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IDGenerator: &SequentialIDGenerator{},
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+	s.Equal("00000000000000000000000000000001", events[0].Contexts["trace"]["trace_id"].(sentry.TraceID).String())
+	s.Equal("0000000000000002", events[0].Contexts["trace"]["span_id"].(sentry.SpanID).String())
+	s.Len(events[0].Spans, 1)
+	s.Equal("0000000000000003", events[0].Spans[0].SpanID.String())
+
+	s.Run("continued trace", func() {
+		s.transport.events = nil
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+		events := s.transport.Events()
+		s.Len(events, 1)
+		s.Equal("d49d9bf66f13450b81f65bc51cf49c03", events[0].Contexts["trace"]["trace_id"].(sentry.TraceID).String())
+	})
+}
+
+func (s *MiddlewareTestSuite) TestIDGeneratorWrappedSpans() {
+	s.e.Renderer = WrapRenderer(&templateRenderer{
+		templates: template.Must(template.New("hello").Parse("Hello, {{.}}!")),
+	})
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IDGenerator: &SequentialIDGenerator{},
+	}))
+	s.e.Use(WrapMiddleware("auth", func(next echo.HandlerFunc) echo.HandlerFunc {
+		return next
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "hello", "World")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Require().Len(events, 1)
+
+	ids := make(map[string]string)
+	for _, span := range events[0].Spans {
+		ids[span.Op] = span.SpanID.String()
+	}
+
+	s.Equal(map[string]string{
+		"http.handler":    "0000000000000003",
+		"middleware":      "0000000000000004",
+		"template.render": "0000000000000005",
+	}, ids)
+}
@@ -0,0 +1,13 @@
+package echosentrymiddleware
+
+import (
+	"sync/atomic"
+)
+
+// inFlightRequests counts requests currently handled by all instances of the middleware
+var inFlightRequests atomic.Int64
+
+// InFlightRequests returns the number of requests currently traced by the middleware
+func InFlightRequests() int64 {
+	return inFlightRequests.Load()
+}
@@ -0,0 +1,24 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestInFlightRequests() {
+	s.e.Use(Middleware())
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		s.EqualValues(1, InFlightRequests())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	s.EqualValues(1, span.Data["http.in_flight"])
+	s.Zero(InFlightRequests())
+}
@@ -0,0 +1,61 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultFlushTimeout is used to flush events on close when the context has no deadline
+const DefaultFlushTimeout = 5 * time.Second
+
+// ErrFlushTimeout is returned on close when not all events were sent in time
+var ErrFlushTimeout = errors.New("echosentrymiddleware: flush timed out")
+
+// NewWithSentry initializes Sentry with the client options and returns the middleware with config
+// and a function flushing pending events (including those held by ErrorDeduplicator and Aggregator),
+// to be called on shutdown.
+// It returns an error if Sentry can't be initialized (e.g. the DSN is invalid).
+func NewWithSentry(
+	clientOptions sentry.ClientOptions, config SentryConfig,
+) (echo.MiddlewareFunc, func(ctx context.Context) error, error) {
+	if err := sentry.Init(clientOptions); err != nil {
+		return nil, nil, fmt.Errorf("echosentrymiddleware: init sentry: %w", err)
+	}
+
+	return MiddlewareWithConfig(config), func(ctx context.Context) error {
+		if config.ErrorDeduplicator != nil {
+			config.ErrorDeduplicator.Flush()
+		}
+
+		if config.Aggregator != nil {
+			config.Aggregator.Flush()
+		}
+
+		timeout := DefaultFlushTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+
+		flushed := make(chan bool, 1)
+
+		go func() {
+			flushed <- sentry.Flush(timeout)
+		}()
+
+		select {
+		case ok := <-flushed:
+			if !ok {
+				return ErrFlushTimeout
+			}
+
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("echosentrymiddleware: flush: %w", ctx.Err())
+		}
+	}, nil
+}
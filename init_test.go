@@ -0,0 +1,62 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestNewWithSentry() {
+	mw, closeFn, err := NewWithSentry(sentry.ClientOptions{
+		EnableTracing: true,
+		Transport:     s.transport,
+	}, SentryConfig{
+		CaptureErrors: true,
+	})
+	s.Require().NoError(err)
+
+	s.e.Use(mw)
+	s.e.GET("/", func(echo.Context) error {
+		return errors.New("test error")
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Len(s.errorEvents(), 1)
+	s.NoError(closeFn(context.Background()))
+
+	s.Run("invalid dsn", func() {
+		mw, closeFn, err := NewWithSentry(sentry.ClientOptions{Dsn: "invalid"}, SentryConfig{})
+		s.Error(err)
+		s.Nil(mw)
+		s.Nil(closeFn)
+	})
+}
+
+func (s *MiddlewareTestSuite) TestNewWithSentryFlushesAggregator() {
+	mw, closeFn, err := NewWithSentry(sentry.ClientOptions{
+		EnableTracing: true,
+		Transport:     s.transport,
+	}, SentryConfig{
+		Aggregator: NewAggregator(time.Hour),
+	})
+	s.Require().NoError(err)
+
+	s.e.Use(mw)
+	s.e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+	s.Empty(s.transport.Events())
+
+	s.NoError(closeFn(context.Background()))
+	s.Len(s.transport.Events(), 1)
+}
@@ -0,0 +1,17 @@
+package echosentrymiddleware
+
+import (
+	"github.com/getsentry/sentry-go"
+)
+
+// startDumpSpan starts a child span measuring the middleware's own work, it is a no-op without InstrumentDumps
+func startDumpSpan(span *sentry.Span, config SentryConfig, description string) func() {
+	if !config.InstrumentDumps {
+		return func() {}
+	}
+
+	child := span.StartChild("middleware.dump", sentry.WithDescription(description), idOption(config.IDGenerator))
+	child.StartTime = config.Clock()
+
+	return finishWithClock(child, config.Clock)
+}
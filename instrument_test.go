@@ -0,0 +1,37 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestInstrumentDumps() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IsBodyDump:      true,
+		InstrumentDumps: true,
+	}))
+	s.e.POST("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "response")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("request"))
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+
+	var descriptions []string
+
+	for _, span := range events[0].Spans {
+		if span.Op == "middleware.dump" {
+			descriptions = append(descriptions, span.Description)
+		}
+	}
+
+	s.ElementsMatch([]string{"read request body", "dump request body", "dump response body"}, descriptions)
+}
@@ -0,0 +1,119 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultMaxJSONRPCPeekSize is the default number of bytes of the request body read to find JSON-RPC methods
+const DefaultMaxJSONRPCPeekSize = 16 << 10
+
+// peekJSONRPCMethods returns the methods of a JSON-RPC call or batch posted to one of JSONRPCRoutes.
+// At most MaxJSONRPCPeekSize bytes are read.
+func peekJSONRPCMethods(c echo.Context, config SentryConfig, request *http.Request) []string {
+	if request.Method != http.MethodPost || request.Body == nil || request.Body == http.NoBody ||
+		!slices.Contains(config.JSONRPCRoutes, c.Path()) {
+		return nil
+	}
+
+	return parseJSONRPCMethods(peekReqBody(request, config.MaxJSONRPCPeekSize))
+}
+
+// parseJSONRPCMethods returns the methods of a call or a batch, a body cut by the peek size still gives
+// the methods before the cut
+func parseJSONRPCMethods(body []byte) []string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		if method, _ := readJSONRPCMethod(dec); method != "" {
+			return []string{method}
+		}
+	case json.Delim('['):
+		var methods []string
+
+		for dec.More() {
+			if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+				break
+			}
+
+			method, ok := readJSONRPCMethod(dec)
+			if method != "" {
+				methods = append(methods, method)
+			}
+
+			if !ok {
+				break
+			}
+		}
+
+		return methods
+	}
+
+	return nil
+}
+
+// readJSONRPCMethod reads the members of an object up to its end and returns its "method"
+func readJSONRPCMethod(dec *json.Decoder) (string, bool) {
+	var method string
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return method, false
+		}
+
+		if key == "method" {
+			if err := dec.Decode(&method); err != nil {
+				return "", false
+			}
+
+			continue
+		}
+
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return method, false
+		}
+	}
+
+	_, err := dec.Token()
+
+	return method, err == nil
+}
+
+// getJSONRPCName returns the transaction name of JSON-RPC calls, the method or "batch"
+func getJSONRPCName(config SentryConfig, methods []string) string {
+	switch len(methods) {
+	case 0:
+		return ""
+	case 1:
+		return guardCardinality(config.CardinalityGuard, methods[0])
+	}
+
+	return "batch"
+}
+
+// dumpJSONRPC tags the method of a call, or records the methods of a batch
+func dumpJSONRPC(span *sentry.Span, config SentryConfig, methods []string) {
+	switch len(methods) {
+	case 0:
+		return
+	case 1:
+		setTag(span, config, "jsonrpc.method", guardCardinality(config.CardinalityGuard, methods[0]))
+		return
+	}
+
+	setTag(span, config, "jsonrpc.batch", "true")
+	span.SetData("jsonrpc.methods", methods)
+}
@@ -0,0 +1,99 @@
+package echosentrymiddleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONRPCMethods(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "call", body: `{"jsonrpc":"2.0","params":{"a":[1,2]},"method":"user.get","id":1}`, want: []string{"user.get"}},
+		{
+			name: "batch",
+			body: `[{"jsonrpc":"2.0","method":"user.get","id":1},{"jsonrpc":"2.0","method":"user.list","id":2}]`,
+			want: []string{"user.get", "user.list"},
+		},
+		{name: "cut", body: `{"jsonrpc":"2.0","method":"user.get","params":{"data":"abc`, want: []string{"user.get"}},
+		{name: "cut batch", body: `[{"method":"user.get"},{"method":"user.list","params":[1,`, want: []string{"user.get", "user.list"}},
+		{name: "no method", body: `{"jsonrpc":"2.0","id":1}`},
+		{name: "not a string", body: `{"method":42}`},
+		{name: "not json", body: `method=user.get`},
+		{name: "empty", body: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseJSONRPCMethods([]byte(tt.body)))
+		})
+	}
+}
+
+func (s *MiddlewareTestSuite) TestJSONRPC() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{JSONRPCRoutes: []string{"/rpc"}, MaxJSONRPCPeekSize: 64}))
+
+	var received string
+	s.e.POST("/rpc", func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		received = string(body)
+
+		return err
+	})
+
+	tests := []struct {
+		name        string
+		body        string
+		transaction string
+		tags        map[string]string
+		methods     []string
+	}{
+		{
+			name:        "call",
+			body:        `{"jsonrpc":"2.0","method":"user.get","params":{"name":"` + strings.Repeat("a", 100) + `"},"id":1}`,
+			transaction: "user.get",
+			tags:        map[string]string{"jsonrpc.method": "user.get"},
+		},
+		{
+			name:        "batch",
+			body:        `[{"method":"user.get","id":1},{"method":"user.list","id":2}]`,
+			transaction: "batch",
+			tags:        map[string]string{"jsonrpc.batch": "true"},
+			methods:     []string{"user.get", "user.list"},
+		},
+		{name: "invalid", body: `not json`, transaction: "HTTP POST /rpc"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body))
+			req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+			s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+			s.Equal(tt.body, received)
+
+			events := s.transport.Events()
+			s.Require().NotEmpty(events)
+			event := events[len(events)-1]
+
+			s.Equal(tt.transaction, event.Transaction)
+
+			for name, value := range tt.tags {
+				s.Equal(value, event.Tags[name])
+			}
+
+			if tt.methods != nil {
+				s.Equal(tt.methods, event.Extra["jsonrpc.methods"])
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package echosentrymiddleware
+
+import (
+	"os"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// KubernetesMetadata describes the pod handling the requests
+type KubernetesMetadata struct {
+	PodName   string
+	Namespace string
+	NodeName  string
+}
+
+// KubernetesMetadataProvider returns the metadata of the pod, it is called once on middleware creation
+type KubernetesMetadataProvider func() KubernetesMetadata
+
+// EnvKubernetesMetadata reads the metadata exposed by the downward API as POD_NAME, POD_NAMESPACE
+// and NODE_NAME environment variables, e.g.
+//
+//	env:
+//	  - name: POD_NAME
+//	    valueFrom:
+//	      fieldRef:
+//	        fieldPath: metadata.name
+func EnvKubernetesMetadata() KubernetesMetadata {
+	return KubernetesMetadata{
+		PodName:   os.Getenv("POD_NAME"),
+		Namespace: os.Getenv("POD_NAMESPACE"),
+		NodeName:  os.Getenv("NODE_NAME"),
+	}
+}
+
+func dumpKubernetesMetadata(span *sentry.Span, config SentryConfig) {
+	metadata := config.kubernetesMetadata
+
+	setTag(span, config, "k8s.pod", metadata.PodName)
+	setTag(span, config, "k8s.namespace", metadata.Namespace)
+	setTag(span, config, "k8s.node", metadata.NodeName)
+}
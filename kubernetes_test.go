@@ -0,0 +1,32 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestKubernetesMetadata() {
+	s.T().Setenv("POD_NAME", "api-7d9f8-x2k4p")
+	s.T().Setenv("POD_NAMESPACE", "shop")
+	s.T().Setenv("NODE_NAME", "node-1")
+
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		KubernetesMetadata: EnvKubernetesMetadata,
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.NotNil(span)
+	s.Equal("api-7d9f8-x2k4p", span.Tags["k8s.pod"])
+	s.Equal("shop", span.Tags["k8s.namespace"])
+	s.Equal("node-1", span.Tags["k8s.node"])
+}
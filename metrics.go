@@ -0,0 +1,24 @@
+package echosentrymiddleware
+
+import "time"
+
+// Metrics records the middleware internals, to monitor the observability pipeline itself.
+// The prometheus submodule provides a Prometheus implementation.
+type Metrics interface {
+	// TransactionStarted is called for every transaction started by the middleware
+	TransactionStarted()
+	// TransactionFinished is called when the transaction is finished, sampled tells whether it is sent
+	TransactionFinished(sampled bool)
+	// BodyDumped is called with the size of the dumped request ("req") or response ("resp") body
+	BodyDumped(kind string, size int)
+	// BodyScrubbed is called with the time spent scrubbing and recording a dumped body
+	BodyScrubbed(duration time.Duration)
+}
+
+// noMetrics is used when config.Metrics is nil
+type noMetrics struct{}
+
+func (noMetrics) TransactionStarted()        {}
+func (noMetrics) TransactionFinished(bool)   {}
+func (noMetrics) BodyDumped(string, int)     {}
+func (noMetrics) BodyScrubbed(time.Duration) {}
@@ -0,0 +1,72 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type metricsRecorder struct {
+	mu       sync.Mutex
+	started  int
+	sampled  int
+	dropped  int
+	dumped   map[string]int
+	scrubbed int
+}
+
+func (m *metricsRecorder) TransactionStarted() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started++
+}
+
+func (m *metricsRecorder) TransactionFinished(sampled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if sampled {
+		m.sampled++
+	} else {
+		m.dropped++
+	}
+}
+
+func (m *metricsRecorder) BodyDumped(kind string, size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dumped[kind] += size
+}
+
+func (m *metricsRecorder) BodyScrubbed(time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scrubbed++
+}
+
+func (s *MiddlewareTestSuite) TestMetrics() {
+	metrics := &metricsRecorder{dumped: make(map[string]int)}
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IsBodyDump: true,
+		Metrics:    metrics,
+	}))
+	s.e.POST("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "response")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("request"))
+	req.Header.Set("sentry-trace", sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("request")))
+
+	s.Equal(2, metrics.started)
+	s.Equal(1, metrics.sampled)
+	s.Equal(1, metrics.dropped)
+	s.Equal(map[string]int{"req": 2 * len("request"), "resp": 2 * len("response")}, metrics.dumped)
+	s.Equal(4, metrics.scrubbed)
+}
@@ -2,13 +2,11 @@
 package echosentrymiddleware
 
 import (
-	"bytes"
-	"io"
+	"cmp"
+	"net"
 	"net/http"
-	"strconv"
+	"time"
 
-	"github.com/adlandh/response-dumper"
-	"github.com/getsentry/sentry-go"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -31,12 +29,270 @@ type (
 		// add req headers & resp headers to tracing tags
 		AreHeadersDump bool
 
+		// RespHeaders dumps response headers passing the filter independently of AreHeadersDump,
+		// e.g. only cache and rate limit headers. Nil follows AreHeadersDump.
+		RespHeaders *HeaderFilter
+
+		// HeadersAsData dumps headers as single "http.request.headers" and "http.response.headers" data entries
+		// instead of a tag per header
+		HeadersAsData bool
+
+		// MaxHeaderTags limits the number of dumped request and response headers each, 0 means no limit
+		MaxHeaderTags int
+
+		// MaxParamTags limits the number of path parameter tags, 0 means no limit
+		MaxParamTags int
+
 		// add req body & resp body to attributes
 		IsBodyDump bool
+
+		// MaxRespDumpSize limits the size of the dumped response body in bytes, 0 means no limit
+		MaxRespDumpSize int
+
+		// RespDumpPolicy defines how the dumped response body is recorded, see RespDumpTruncate,
+		// RespDumpDrop and RespDumpAttach
+		RespDumpPolicy RespDumpPolicy
+
+		// DumpReqBodyMethods defines methods of requests whose body is dumped, default is POST, PUT, PATCH and DELETE
+		DumpReqBodyMethods []string
+
+		// JSONRPCRoutes are echo routes of JSON-RPC endpoints, the "method" of calls posted to them names
+		// the transaction and is tagged as "jsonrpc.method"
+		JSONRPCRoutes []string
+
+		// MaxJSONRPCPeekSize limits the bytes of the request body read to find JSON-RPC methods,
+		// default is DefaultMaxJSONRPCPeekSize
+		MaxJSONRPCPeekSize int
+
+		// SOAPRoutes are echo routes of SOAP endpoints, the action or operation of calls posted to them names
+		// the transaction and is tagged as "soap.action" or "soap.operation"
+		SOAPRoutes []string
+
+		// RPCRoutes are echo routes of Connect, gRPC-web and gRPC endpoints, "package.Service/Method" of calls to them
+		// names the transaction
+		RPCRoutes []string
+
+		// OpNameTemplate defines the span op, placeholders are {method}, {route}, {route_name} and {service},
+		// default is DefaultOpNameTemplate
+		OpNameTemplate string
+
+		// ServiceName is used for the {service} placeholder of OpNameTemplate
+		ServiceName string
+
+		// UseRouteName uses the explicit name of the matched echo route (e.g. "checkout.create") as
+		// the transaction name. Routes named after their handlers by echo are ignored.
+		UseRouteName bool
+
+		// AddFingerprintTag adds a "fingerprint" tag computed from method, route and normalized query keys
+		AddFingerprintTag bool
+
+		// AddVersionTags adds echo.version, middleware.version and go.version tags
+		AddVersionTags bool
+
+		// AddBuildInfo adds vcs.revision, vcs.time, vcs.modified and app.version tags and the "build" context
+		// read from the build info of the binary
+		AddBuildInfo bool
+
+		// Environment overrides the environment of ClientOptions for the transactions and events of the requests,
+		// useful when one process serves several logical services
+		Environment string
+
+		// Release overrides the release of ClientOptions for the transactions and events of the requests
+		Release string
+
+		// ServerIdentity provides the server identity tagged on every transaction, e.g. EnvServerIdentity.
+		// Nil disables the tags.
+		ServerIdentity ServerIdentityProvider
+
+		// KubernetesMetadata provides pod name, namespace and node tagged on every transaction,
+		// e.g. EnvKubernetesMetadata. Nil disables the tags.
+		KubernetesMetadata KubernetesMetadataProvider
+
+		// CloudMetadata provides cloud provider, region, zone and instance type tagged on every transaction.
+		// Nil disables the tags.
+		CloudMetadata CloudMetadataProvider
+
+		// CardinalityGuard replaces high-cardinality values in request_uri and path parameter tags
+		CardinalityGuard CardinalityGuard
+
+		// IPExtractor extracts client_ip from the request, nil uses c.RealIP()
+		IPExtractor echo.IPExtractor
+
+		// TagBasicAuthUser enables the "user" tag with the basic auth username, the username is personal data
+		// so it is opt-in. The password is never dumped, credential headers are masked even with AreHeadersDump.
+		TagBasicAuthUser bool
+
+		// APIKeyHeader defines the header with the API key of the request, used with APIKeyResolver
+		APIKeyHeader string
+
+		// APIKeyResolver resolves the user owning the API key, the user is set on the request scope and tagged
+		APIKeyResolver APIKeyResolver
+
+		// FlagsFn returns feature flags of the request, recorded as span data and the flags context of events
+		FlagsFn FlagsFn
+
+		// ExperimentsFn tags experiment assignments as "experiment.<name>", e.g. ExperimentsFromHeader
+		ExperimentsFn ExperimentsFn
+
+		// TenantFn tags the tenant as "tenant" on transactions and events, e.g. TenantFromHeader
+		TenantFn TenantFn
+
+		// TenantSampling defines sample rates per tenant, nil uses the SDK sampling
+		TenantSampling *TenantSampling
+
+		// ContextTags maps keys of values stored with c.Set (e.g. by auth middleware) to tag names,
+		// an empty tag name uses the key
+		ContextTags map[string]string
+
+		// TrustedProxies defines CIDRs (or single IPs) of proxies trusted to set X-Forwarded-For.
+		// If set, client_ip is resolved from the forwarding chain instead of echo's RealIP.
+		TrustedProxies []string
+
+		// SensitiveFields defines names of fields scrubbed from structured bodies (case-insensitive, partial match),
+		// default is DefaultSensitiveFields
+		SensitiveFields []string
+
+		// URLScrubber rewrites the request URI before it is sent, default is NewURLScrubber with SensitiveFields
+		URLScrubber URLScrubber
+
+		// Sanitizer normalizes tag names and values, default is DefaultSanitizer
+		Sanitizer Sanitizer
+
+		// DetectPII masks emails, card numbers and JWTs found in dumped bodies and headers
+		DetectPII bool
+
+		// OmitRequestURI drops the request_uri tag and names transactions after routes instead of raw URIs,
+		// query parameters are recorded as "http.query" data with sensitive values masked
+		OmitRequestURI bool
+
+		// DumpRespBodyOnErrorOnly limits response body dumping to 4xx/5xx responses, requires IsBodyDump
+		DumpRespBodyOnErrorOnly bool
+
+		// MaxQueueTime limits the load balancer queue time accepted from X-Request-Start and X-Queue-Start,
+		// older timestamps are dropped, default is DefaultMaxQueueTime
+		MaxQueueTime time.Duration
+
+		// SlowRequestThreshold marks slower requests with the "slow" tag and attaches runtime stats
+		// (goroutines, heap, GC), 0 disables it
+		SlowRequestThreshold time.Duration
+
+		// DetachSpanContext keeps the transaction alive when the client disconnects, it is sent with
+		// the "cancelled" status. The handler is cancelled with the request as usual.
+		DetachSpanContext bool
+
+		// FinishAfterResponse flushes the response before finishing the span, so its duration includes
+		// writing the body to the client
+		FinishAfterResponse bool
+
+		// HeartbeatInterval adds a "request.heartbeat" breadcrumb every interval while the handler runs,
+		// 0 disables it
+		HeartbeatInterval time.Duration
+
+		// UploadSpansMinSize records an "http.upload" child span per part of multipart requests with at least
+		// this Content-Length (or of unknown length), 0 disables it
+		UploadSpansMinSize int64
+
+		// Clock returns the current time used for span timestamps and durations, default is time.Now
+		Clock func() time.Time
+
+		// CaptureErrors sends errors returned by handlers to Sentry as error events
+		CaptureErrors bool
+
+		// CaptureEventOnStatus defines statuses of responses captured as error events (including responses
+		// written without an error), e.g. {{From: 500, To: 599}, {From: 429, To: 429}}.
+		// Other statuses are ignored. If empty, CaptureErrors decides.
+		CaptureEventOnStatus []StatusRange
+
+		// ContextsFn returns named contexts set on the request scope before the handler
+		ContextsFn ContextsFn
+
+		// AttachmentsFn returns attachments sent with the transaction and the error events of the request
+		AttachmentsFn AttachmentsFn
+
+		// CaptureInvalidWebhookSignatures captures ErrInvalidWebhookSignature events for invalid signatures
+		// recorded with SetWebhookSignature
+		CaptureInvalidWebhookSignatures bool
+
+		// SecurityEvents captures warning events for suspicious traffic, see NewSecurityEvents. Nil disables them.
+		SecurityEvents *SecurityEvents
+
+		// ErrorLimiter limits error events captured by the middleware, nil means no limit
+		ErrorLimiter *ErrorLimiter
+
+		// ErrorDeduplicator merges identical errors captured by the middleware within a window, nil means no deduplication.
+		// Repeats not sent yet are lost if the application exits without calling its Flush (NewWithSentry does it).
+		ErrorDeduplicator *ErrorDeduplicator
+
+		// ErrorHandling defines whether errors of the handler are passed to c.Error, returned, or both (default)
+		ErrorHandling ErrorHandlingMode
+
+		// OnInternalError is called with ErrInternal on failures of the middleware itself, they never break the request
+		OnInternalError func(err error, c echo.Context)
+
+		// InstrumentDumps records child spans (op "middleware.dump") for reading, scrubbing and dumping bodies
+		InstrumentDumps bool
+
+		// IDGenerator supplies trace and span IDs instead of random ones, see SequentialIDGenerator
+		IDGenerator IDGenerator
+
+		// Aggregator sends summarized transactions per route and interval instead of a transaction per request,
+		// see NewAggregator. Nil sends every sampled transaction.
+		Aggregator *Aggregator
+
+		// TransactionQuota caps sampled transactions sent per second, see NewTransactionQuota. Nil means no cap.
+		TransactionQuota *TransactionQuota
+
+		// Metrics records metrics of the middleware internals, e.g. with the prometheus submodule.
+		// Nil records nothing.
+		Metrics Metrics
+
+		// SpanMirror receives a copy of the HTTP server span, e.g. from the otel submodule. Nil disables mirroring.
+		SpanMirror SpanMirror
+
+		// NestedPolicy defines what this registration does when another one of the middleware already handles
+		// the request, default is NestedChildSpan
+		NestedPolicy NestedPolicy
+
+		// DebugSampleHeader defines the header forcing sampling, default is DefaultDebugSampleHeader
+		DebugSampleHeader string
+
+		// DebugSampleSecret is the shared secret the DebugSampleHeader must carry, empty disables forced sampling.
+		// The header value is never dumped.
+		DebugSampleSecret string
+
+		// ParentSampling defines how the sampled flag of a continued trace is treated
+		ParentSampling ParentSamplingPolicy
+
+		trustedProxies     []*net.IPNet
+		routeNames         *routeNames
+		staticRoutes       *staticRoutes
+		opNameTemplate     opNameTemplate
+		serverIdentity     ServerIdentity
+		kubernetesMetadata KubernetesMetadata
+		cloudMetadata      CloudMetadata
 	}
 )
 
+// RespDumpPolicy defines how the dumped response body is recorded
+type RespDumpPolicy int
+
+const (
+	// RespDumpTruncate records the response body truncated to MaxRespDumpSize
+	RespDumpTruncate RespDumpPolicy = iota
+	// RespDumpDrop drops response bodies bigger than MaxRespDumpSize
+	RespDumpDrop
+	// RespDumpAttach adds the response body (up to MaxRespDumpSize) as an attachment of error events
+	// instead of a tag
+	RespDumpAttach
+)
+
+// DefaultOpNameTemplate is the default template of the span op
+const DefaultOpNameTemplate = "HTTP {method} {route}"
+
 var (
+	// DefaultDumpReqBodyMethods is the default list of methods of requests whose body is dumped
+	DefaultDumpReqBodyMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
 	// DefaultSentryConfig is the default Sentry Performance middleware config.
 	DefaultSentryConfig = SentryConfig{
 		Skipper:        middleware.DefaultSkipper,
@@ -52,132 +308,90 @@ func Middleware() echo.MiddlewareFunc {
 
 // MiddlewareWithConfig returns a OpenTelemetry middleware with config.
 func MiddlewareWithConfig(config SentryConfig) echo.MiddlewareFunc {
-	if config.Skipper == nil {
-		config.Skipper = middleware.DefaultSkipper
-	}
-
-	if config.BodySkipper == nil {
-		config.BodySkipper = defaultBodySkipper
-	}
+	config = normalizeConfig(config)
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			stats.requestsSeen.Add(1)
+
 			if config.Skipper(c) || c.Request() == nil || c.Response() == nil {
+				stats.requestsSkipped.Add(1)
 				return next(c)
 			}
 
-			request, span, endSpan := createSpan(c)
-			defer endSpan()
-
-			ctx := span.Context()
-
-			setTag(span, "client_ip", c.RealIP())
-			setTag(span, "remote_addr", request.RemoteAddr)
-			setTag(span, "request_uri", request.RequestURI)
-			setTag(span, "path", c.Path())
-
-			skipReqBody, skipRespBody := config.BodySkipper(c)
-
-			respDumper := dumpReq(c, config, span, request, skipReqBody)
-
-			// setup request context - add span
-			c.SetRequest(request.WithContext(ctx))
-
-			// call next middleware / controller
-			err := next(c)
-			if err != nil {
-				setTag(span, "echo.error", err.Error())
-				c.Error(err) // call custom registered error handler
-			}
-
-			dumpResp(c, config, span, respDumper, skipRespBody)
-
-			return err
+			return handleRequest(c, config, next)
 		}
 	}
 }
 
-func dumpResp(c echo.Context, config SentryConfig, span *sentry.Span, respDumper *response.Dumper, skipRespBody bool) {
-	setTag(span, "request_id", getRequestID(c))
-	span.Status = sentry.HTTPtoSpanStatus(c.Response().Status)
-	setTag(span, "resp.status", strconv.Itoa(c.Response().Status))
-
-	// Dump response headers
-	if config.AreHeadersDump {
-		for k := range c.Response().Header() {
-			setTag(span, "resp.header."+k, c.Response().Header().Get(k))
-		}
+func normalizeConfig(config SentryConfig) SentryConfig {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
 	}
 
-	// Dump response body
-	if config.IsBodyDump {
-		respBody := respDumper.GetResponse()
+	if config.BodySkipper == nil {
+		config.BodySkipper = defaultBodySkipper
+	}
 
-		if respBody != "" && skipRespBody {
-			respBody = "[excluded]"
-		}
+	config.trustedProxies = parseTrustedProxies(config.TrustedProxies)
+	config.routeNames = &routeNames{}
+	config.staticRoutes = &staticRoutes{}
 
-		setTag(span, "resp.body", respBody)
+	if config.Clock == nil {
+		config.Clock = time.Now
 	}
-}
 
-func dumpReq(c echo.Context, config SentryConfig, span *sentry.Span, request *http.Request, skipReqBody bool) *response.Dumper {
-	if username, _, ok := request.BasicAuth(); ok {
-		setTag(span, "user", username)
+	if config.OpNameTemplate == "" {
+		config.OpNameTemplate = DefaultOpNameTemplate
 	}
 
-	// Add path parameters
-	for _, paramName := range c.ParamNames() {
-		setTag(span, "path."+paramName, c.Param(paramName))
-	}
+	config.opNameTemplate = parseOpNameTemplate(config.OpNameTemplate)
 
-	// Dump request headers
-	if config.AreHeadersDump {
-		for k := range request.Header {
-			setTag(span, "req.header."+k, request.Header.Get(k))
-		}
+	if config.SensitiveFields == nil {
+		config.SensitiveFields = DefaultSensitiveFields
 	}
 
-	// Dump request & response body
-	var respDumper *response.Dumper
+	if config.URLScrubber == nil {
+		config.URLScrubber = NewURLScrubber(config.SensitiveFields, DefaultURLScrubberMaxDigits)
+	}
 
-	if config.IsBodyDump {
-		// request
-		if request.Body != nil {
-			reqBody := []byte("[excluded]")
+	if config.DebugSampleHeader == "" {
+		config.DebugSampleHeader = DefaultDebugSampleHeader
+	}
 
-			if !skipReqBody {
-				var err error
+	if config.Sanitizer == nil {
+		config.Sanitizer = DefaultSanitizer{}
+	}
 
-				reqBody, err = io.ReadAll(request.Body)
-				if err == nil {
-					_ = request.Body.Close()
-					request.Body = io.NopCloser(bytes.NewBuffer(reqBody)) // reset original request body
-				}
-			}
+	if config.Metrics == nil {
+		config.Metrics = noMetrics{}
+	}
 
-			setTag(span, "req.body", string(reqBody))
-		}
+	if config.DumpReqBodyMethods == nil {
+		config.DumpReqBodyMethods = DefaultDumpReqBodyMethods
+	}
 
-		// response
-		respDumper = response.NewDumper(c.Response().Writer)
-		c.Response().Writer = respDumper
+	if config.MaxQueueTime <= 0 {
+		config.MaxQueueTime = DefaultMaxQueueTime
 	}
 
-	return respDumper
-}
+	if config.MaxJSONRPCPeekSize <= 0 {
+		config.MaxJSONRPCPeekSize = DefaultMaxJSONRPCPeekSize
+	}
 
-func createSpan(c echo.Context) (*http.Request, *sentry.Span, func()) {
-	request := c.Request()
-	savedCtx := request.Context()
-	opname := "HTTP " + request.Method + " " + c.Path()
-	tname := "HTTP " + request.Method + " " + c.Request().RequestURI
-	span := sentry.StartSpan(savedCtx, opname, sentry.WithTransactionName(tname))
+	if config.ServerIdentity != nil {
+		config.serverIdentity = config.ServerIdentity()
+	}
 
-	return request, span, func() {
-		request = request.WithContext(savedCtx)
-		c.SetRequest(request)
+	if config.KubernetesMetadata != nil {
+		config.kubernetesMetadata = config.KubernetesMetadata()
+	}
 
-		defer span.Finish()
+	if config.CloudMetadata != nil {
+		config.cloudMetadata = config.CloudMetadata()
+		config.serverIdentity.AvailabilityZone = cmp.Or(config.cloudMetadata.AvailabilityZone,
+			config.serverIdentity.AvailabilityZone)
 	}
+
+	return config
 }
@@ -1,6 +1,7 @@
 package echosentrymiddleware
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -77,6 +78,7 @@ func (s *MiddlewareTestSuite) TestMiddleware() {
 			s.NotEmpty(span.Tags["client_ip"])
 			s.Equal(echo.MIMEApplicationJSON, span.Tags[contentTypeHeader])
 			s.Equal("test", span.Tags[testHeader])
+			s.Equal(2, span.Data["req.headers.count"])
 			return c.String(http.StatusOK, "test")
 		})
 
@@ -209,6 +211,140 @@ func (s *MiddlewareTestSuite) TestMiddlewareWithConfig() {
 	})
 }
 
+func (s *MiddlewareTestSuite) TestDumpRespBodyOnErrorOnly() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IsBodyDump:              true,
+		DumpRespBodyOnErrorOnly: true,
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/:status", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		status, _ := strconv.Atoi(c.Param("status"))
+		return c.String(status, "test")
+	})
+
+	s.Run("success", func() {
+		req := httptest.NewRequest(http.MethodGet, "/200", nil)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		s.Equal("test", rec.Body.String())
+		s.Empty(span.Tags["resp.body"])
+	})
+
+	s.Run("error", func() {
+		req := httptest.NewRequest(http.MethodGet, "/400", nil)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		s.Equal("test", rec.Body.String())
+		s.Equal("test", span.Tags["resp.body"])
+	})
+}
+
+func (s *MiddlewareTestSuite) TestDumpNonFlusherWriter() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+
+	var isFlusher, isHijacker bool
+	s.e.GET("/", func(c echo.Context) error {
+		_, isFlusher = c.Response().Writer.(http.Flusher)
+		_, isHijacker = c.Response().Writer.(http.Hijacker)
+		return c.String(http.StatusOK, "test")
+	})
+
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(struct{ http.ResponseWriter }{rec}, httptest.NewRequest(http.MethodGet, "/", nil))
+	s.Equal("test", rec.Body.String())
+	s.False(isFlusher)
+	s.False(isHijacker)
+
+	rec = httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	s.True(isFlusher)
+	s.False(isHijacker)
+}
+
+func (s *MiddlewareTestSuite) TestDumpReqBodyMethods() {
+	s.Run("default", func() {
+		e := echo.New()
+		e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+
+		var span *sentry.Span
+		e.Any("/", func(c echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", strings.NewReader("testBody"))
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		s.Empty(span.Tags["req.body"])
+
+		req = httptest.NewRequest(http.MethodDelete, "/", strings.NewReader("testBody"))
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		s.Equal("testBody", span.Tags["req.body"])
+	})
+
+	s.Run("custom", func() {
+		e := echo.New()
+		e.Use(MiddlewareWithConfig(SentryConfig{
+			IsBodyDump:         true,
+			DumpReqBodyMethods: []string{http.MethodPut},
+		}))
+
+		var span *sentry.Span
+		e.Any("/", func(c echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("testBody"))
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		s.Empty(span.Tags["req.body"])
+
+		req = httptest.NewRequest(http.MethodPut, "/", strings.NewReader("testBody"))
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		s.Equal("testBody", span.Tags["req.body"])
+	})
+}
+
+func (s *MiddlewareTestSuite) TestClientCanceled() {
+	s.e.Use(Middleware())
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	s.NotNil(span)
+	s.Equal(sentry.SpanStatusCanceled, span.Status)
+	s.Equal(strconv.Itoa(statusClientClosedRequest), span.Tags[respStatus])
+}
+
 func TestMiddleware(t *testing.T) {
 	suite.Run(t, new(MiddlewareTestSuite))
 }
+
+func (s *MiddlewareTestSuite) TestHandlerSpan() {
+	s.e.Use(Middleware())
+	s.e.GET("/test", func(c echo.Context) error {
+		span := sentry.SpanFromContext(c.Request().Context())
+		s.Equal("http.handler", span.Op)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+	s.Len(events[0].Spans, 1)
+	s.Equal("http.handler", events[0].Spans[0].Op)
+	s.Equal("/test", events[0].Spans[0].Description)
+}
@@ -0,0 +1,67 @@
+package echosentrymiddleware
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SpanMirror receives a copy of the HTTP server span in addition to Sentry, e.g. while migrating
+// between the backends. The otel submodule mirrors spans to an OpenTelemetry tracer provider.
+type SpanMirror interface {
+	// Start starts the mirrored span and returns the context passed to the handler
+	// and a function ending the span with the response status (0 if unknown) and the error of the handler
+	Start(ctx context.Context, span MirroredSpan) (context.Context, func(status int, err error))
+}
+
+// MirroredSpan describes the request of the mirrored span
+type MirroredSpan struct {
+	// Name is the method and the route, e.g. "GET /users/:id"
+	Name   string
+	Method string
+	// Route is the route pattern, empty when the request matched no route
+	Route string
+	// Path is the scrubbed URL path
+	Path string
+}
+
+// mirroredSpan is a started span of config.SpanMirror
+type mirroredSpan struct {
+	finish   func(status int, err error)
+	savedCtx context.Context
+}
+
+// startMirroredSpan starts the mirrored span and puts its context into the request,
+// so spans of the mirrored backend are nested under it. It returns nil without a mirror.
+func startMirroredSpan(c echo.Context, config SentryConfig) *mirroredSpan {
+	if config.SpanMirror == nil {
+		return nil
+	}
+
+	request := c.Request()
+
+	ctx, finish := config.SpanMirror.Start(request.Context(), MirroredSpan{
+		Name:   request.Method + " " + getRoute(c, config),
+		Method: request.Method,
+		Route:  c.Path(),
+		Path:   config.URLScrubber(request.URL.Path),
+	})
+
+	c.SetRequest(request.WithContext(ctx))
+
+	return &mirroredSpan{finish: finish, savedCtx: request.Context()}
+}
+
+// end finishes the mirrored span and restores the request context, unless the handler was abandoned
+// by echo's Timeout middleware and still uses the context
+func (s *mirroredSpan) end(c echo.Context, status int, err error, timedOut bool) {
+	if s == nil {
+		return
+	}
+
+	if !timedOut {
+		c.SetRequest(c.Request().WithContext(s.savedCtx))
+	}
+
+	s.finish(status, err)
+}
@@ -0,0 +1,39 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+)
+
+type mirrorKey struct{}
+
+type spanMirrorRecorder struct {
+	started MirroredSpan
+	status  int
+}
+
+func (m *spanMirrorRecorder) Start(ctx context.Context, span MirroredSpan) (context.Context, func(status int, err error)) {
+	m.started = span
+
+	return context.WithValue(ctx, mirrorKey{}, true), func(status int, _ error) {
+		m.status = status
+	}
+}
+
+func (s *MiddlewareTestSuite) TestSpanMirror() {
+	mirror := &spanMirrorRecorder{}
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{SpanMirror: mirror}))
+	s.e.GET("/users/:id", func(c echo.Context) error {
+		s.Equal(true, c.Request().Context().Value(mirrorKey{}))
+		return c.NoContent(http.StatusServiceUnavailable)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	s.Equal(MirroredSpan{Name: "GET /users/:id", Method: http.MethodGet, Route: "/users/:id", Path: "/users/42"}, mirror.started)
+	s.Equal(http.StatusServiceUnavailable, mirror.status)
+}
@@ -0,0 +1,66 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// NestedPolicy defines what a registration of the middleware does when another one already handles
+// the request, e.g. when it is attached to both e.Use and a Group
+type NestedPolicy int
+
+const (
+	// NestedChildSpan records the inner registration as a child span of the outer transaction
+	NestedChildSpan NestedPolicy = iota
+	// NestedSkip makes the inner registration a no-op
+	NestedSkip
+)
+
+// handledKey marks request contexts already handled by the middleware
+type handledKey struct{}
+
+// handledBy holds the settings of the registration handling the request, for spans started
+// by WrapMiddleware and WrapRenderer
+type handledBy struct {
+	clock       func() time.Time
+	idGenerator IDGenerator
+}
+
+func markHandled(ctx context.Context, config SentryConfig) context.Context {
+	return context.WithValue(ctx, handledKey{}, handledBy{clock: config.Clock, idGenerator: config.IDGenerator})
+}
+
+// startHandledSpan starts a child span of the request context with the clock and IDs of the registration
+// handling the request, the returned function finishes it
+func startHandledSpan(ctx context.Context, operation, description string) (*sentry.Span, func()) {
+	handled, _ := ctx.Value(handledKey{}).(handledBy)
+
+	clock := handled.clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	span := sentry.StartSpan(ctx, operation, sentry.WithDescription(description), idOption(handled.idGenerator))
+	span.StartTime = clock()
+
+	return span, finishWithClock(span, clock)
+}
+
+// finishWithClock returns a function finishing the span at the time of the clock, only the first call counts
+func finishWithClock(span *sentry.Span, clock func() time.Time) func() {
+	return func() {
+		if !span.EndTime.IsZero() {
+			return
+		}
+
+		span.EndTime = clock()
+		span.Finish()
+	}
+}
+
+func isHandled(c echo.Context) bool {
+	return c.Request().Context().Value(handledKey{}) != nil
+}
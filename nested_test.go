@@ -0,0 +1,94 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestExistingTransaction() {
+	s.e.Use(Middleware())
+	s.e.Use(Middleware())
+	s.e.GET("/", func(c echo.Context) error {
+		span := sentry.SpanFromContext(c.Request().Context())
+		s.NotNil(span)
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+	s.Equal("transaction", events[0].Type)
+
+	var nested *sentry.Span
+
+	for _, span := range events[0].Spans {
+		if span.Op == "HTTP GET /" {
+			nested = span
+		}
+	}
+
+	s.Require().NotNil(nested)
+	s.Equal("HTTP GET /", nested.Description)
+	s.Equal("200", nested.Tags[respStatus])
+}
+
+func (s *MiddlewareTestSuite) TestNestedCapturesOnce() {
+	var handlerCalls int
+
+	s.e.HTTPErrorHandler = func(err error, c echo.Context) {
+		handlerCalls++
+		s.e.DefaultHTTPErrorHandler(err, c)
+	}
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{CaptureErrors: true, ErrorHandling: ErrorCallAndSwallow}))
+
+	g := s.e.Group("/api", MiddlewareWithConfig(SentryConfig{CaptureErrors: true}))
+	g.GET("/users", func(echo.Context) error {
+		return errors.New("test error")
+	})
+
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+
+	s.Equal(http.StatusInternalServerError, rec.Code)
+	s.Equal(1, handlerCalls)
+	s.Len(s.errorEvents(), 1)
+}
+
+func (s *MiddlewareTestSuite) TestNestedSkip() {
+	var bodySkipperCalls int
+
+	s.e.Use(Middleware())
+
+	g := s.e.Group("/api", MiddlewareWithConfig(SentryConfig{
+		NestedPolicy: NestedSkip,
+		BodySkipper: func(echo.Context) (bool, bool) {
+			bodySkipperCalls++
+			return false, false
+		},
+	}))
+	g.GET("/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Zero(bodySkipperCalls)
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+
+	for _, span := range events[0].Spans {
+		s.NotEqual("HTTP GET /api/users", span.Op)
+	}
+}
@@ -0,0 +1,61 @@
+package echosentrymiddleware
+
+import (
+	"cmp"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPMiddleware returns a net/http Sentry middleware with default config
+func HTTPMiddleware() func(http.Handler) http.Handler {
+	return HTTPMiddlewareWithConfig(DefaultSentryConfig)
+}
+
+// HTTPMiddlewareWithConfig returns a net/http Sentry middleware with config.
+// It shares the core with the echo middleware, so Skipper and BodySkipper receive
+// an echo.Context wrapping the original request and response writer.
+// Set config.IPExtractor to apply the same IP logic as the rest of the application.
+func HTTPMiddlewareWithConfig(config SentryConfig) func(http.Handler) http.Handler {
+	config = normalizeConfig(config)
+	e := echo.New()
+	e.IPExtractor = config.IPExtractor
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c := e.NewContext(r, w)
+			c.SetPath(httpRoute(config, r))
+
+			next := func(c echo.Context) error {
+				handler.ServeHTTP(c.Response(), c.Request())
+				return nil
+			}
+
+			stats.requestsSeen.Add(1)
+
+			if config.Skipper(c) {
+				stats.requestsSkipped.Add(1)
+				_ = next(c)
+
+				return
+			}
+
+			_ = handleRequest(c, config, next)
+		})
+	}
+}
+
+// httpRoute returns the ServeMux pattern that matched the request without its method,
+// or the request path with high-cardinality values replaced when the request is not routed yet
+func httpRoute(config SentryConfig, r *http.Request) string {
+	if pattern := requestPattern(r); pattern != "" {
+		if _, path, ok := strings.Cut(pattern, " "); ok {
+			return path
+		}
+
+		return pattern
+	}
+
+	return guardCardinality(cmp.Or(config.CardinalityGuard, CardinalityGuardPlaceholder), r.URL.Path)
+}
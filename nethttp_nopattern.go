@@ -0,0 +1,10 @@
+//go:build !go1.23
+
+package echosentrymiddleware
+
+import "net/http"
+
+// requestPattern is empty before go1.23, which has no http.Request.Pattern
+func requestPattern(*http.Request) string {
+	return ""
+}
@@ -0,0 +1,9 @@
+//go:build go1.23
+
+package echosentrymiddleware
+
+import "net/http"
+
+func requestPattern(r *http.Request) string {
+	return r.Pattern
+}
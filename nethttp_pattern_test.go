@@ -0,0 +1,24 @@
+//go:build go1.23
+
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+)
+
+func (s *MiddlewareTestSuite) TestHTTPMiddlewarePattern() {
+	var span *sentry.Span
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{id}", HTTPMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		span = sentry.TransactionFromContext(r.Context())
+	})))
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	s.Require().NotNil(span)
+	s.Equal("/users/{id}", span.Tags["path"])
+}
@@ -0,0 +1,63 @@
+package echosentrymiddleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+func (s *MiddlewareTestSuite) TestHTTPMiddleware() {
+	var span *sentry.Span
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		span = sentry.TransactionFromContext(r.Context())
+		s.NotNil(span)
+		s.NotEmpty(span.Tags["client_ip"])
+		s.Equal("/test", span.Tags["path"])
+		s.Equal("test", span.Tags[testHeader])
+		s.Equal("testBody", span.Tags["req.body"])
+
+		body, err := io.ReadAll(r.Body)
+		s.NoError(err)
+		s.Equal("testBody", string(body))
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("test"))
+	})
+
+	handler := HTTPMiddlewareWithConfig(SentryConfig{
+		AreHeadersDump: true,
+		IsBodyDump:     true,
+	})(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("testBody"))
+	req.Header.Set("testHeader", "test")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	s.Equal(http.StatusCreated, rec.Code)
+	s.Equal("test", rec.Body.String())
+	s.NotNil(span)
+	s.Equal(sentry.HTTPtoSpanStatus(http.StatusCreated), span.Status)
+	s.Equal(strconv.Itoa(http.StatusCreated), span.Tags[respStatus])
+	s.Equal("test", span.Tags["resp.body"])
+}
+
+func (s *MiddlewareTestSuite) TestHTTPMiddlewareUnroutedPath() {
+	var span *sentry.Span
+
+	handler := HTTPMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		span = sentry.TransactionFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1234567/orders/d5e2c7a0-1b6f-4a8e-9c1d-2f3b4a5c6d7e", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Require().NotNil(span)
+	s.Equal("/users/{number}/orders/{uuid}", span.Tags["path"])
+}
@@ -0,0 +1,101 @@
+package echosentrymiddleware
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+var (
+	_ http.Flusher  = flushingWriter{}
+	_ http.Hijacker = hijackingWriter{}
+	_ http.Flusher  = flushingHijackingWriter{}
+	_ http.Hijacker = flushingHijackingWriter{}
+)
+
+// wrappingWriter is a response writer installed by the middleware in front of the original one.
+// Push and ReadFrom degrade gracefully (http.ErrNotSupported and io.Copy) when the original writer lacks them.
+type wrappingWriter interface {
+	http.ResponseWriter
+	http.Pusher
+	io.ReaderFrom
+	Unwrap() http.ResponseWriter
+}
+
+// exposeOptional returns the writer implementing http.Flusher and http.Hijacker only when the writer it wraps does,
+// so handlers checking for them, like SSE or websocket ones, are not misled
+func exposeOptional(w wrappingWriter) http.ResponseWriter {
+	_, isFlusher := w.Unwrap().(http.Flusher)
+	_, isHijacker := w.Unwrap().(http.Hijacker)
+
+	switch {
+	case isFlusher && isHijacker:
+		return flushingHijackingWriter{w}
+	case isFlusher:
+		return flushingWriter{w}
+	case isHijacker:
+		return hijackingWriter{w}
+	default:
+		return w
+	}
+}
+
+// unexposeOptional returns the writer passed to exposeOptional
+func unexposeOptional(w http.ResponseWriter) http.ResponseWriter {
+	switch w := w.(type) {
+	case flushingWriter:
+		return w.wrappingWriter
+	case hijackingWriter:
+		return w.wrappingWriter
+	case flushingHijackingWriter:
+		return w.wrappingWriter
+	default:
+		return w
+	}
+}
+
+func flush(w wrappingWriter) {
+	w.Unwrap().(http.Flusher).Flush()
+}
+
+func hijack(w wrappingWriter) (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.Unwrap().(http.Hijacker).Hijack()
+	if err != nil {
+		err = fmt.Errorf("error hijacking response: %w", err)
+	}
+
+	return conn, rw, err
+}
+
+// flushingWriter wraps a writer in front of an http.Flusher
+type flushingWriter struct {
+	wrappingWriter
+}
+
+func (w flushingWriter) Flush() {
+	flush(w.wrappingWriter)
+}
+
+// hijackingWriter wraps a writer in front of an http.Hijacker
+type hijackingWriter struct {
+	wrappingWriter
+}
+
+func (w hijackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return hijack(w.wrappingWriter)
+}
+
+// flushingHijackingWriter wraps a writer in front of an http.Flusher and http.Hijacker
+type flushingHijackingWriter struct {
+	wrappingWriter
+}
+
+func (w flushingHijackingWriter) Flush() {
+	flush(w.wrappingWriter)
+}
+
+func (w flushingHijackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return hijack(w.wrappingWriter)
+}
@@ -0,0 +1,52 @@
+// Package echosentryotel mirrors HTTP server spans of the echo Sentry middleware to an OpenTelemetry
+// tracer provider, e.g. while migrating between the backends
+package echosentryotel
+
+import (
+	"context"
+	"net/http"
+
+	echosentrymiddleware "github.com/adlandh/echo-sentry-middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/adlandh/echo-sentry-middleware"
+
+// NewSpanMirror returns a mirror starting spans with the provider, set it as SentryConfig.SpanMirror.
+// Spans of OTel instrumented code called by the handlers are nested under the mirrored span.
+func NewSpanMirror(provider trace.TracerProvider) echosentrymiddleware.SpanMirror {
+	return &spanMirror{tracer: provider.Tracer(tracerName)}
+}
+
+type spanMirror struct {
+	tracer trace.Tracer
+}
+
+func (m *spanMirror) Start(ctx context.Context, mirrored echosentrymiddleware.MirroredSpan) (context.Context, func(int, error)) {
+	ctx, span := m.tracer.Start(ctx, mirrored.Name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", mirrored.Method),
+			attribute.String("http.route", mirrored.Route),
+			attribute.String("url.path", mirrored.Path),
+		),
+	)
+
+	return ctx, func(status int, err error) {
+		if status > 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+		}
+
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		if err != nil || status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		span.End()
+	}
+}
@@ -0,0 +1,42 @@
+package echosentryotel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echosentrymiddleware "github.com/adlandh/echo-sentry-middleware"
+	"github.com/adlandh/echo-sentry-middleware/sentrytest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanMirror(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	harness, err := sentrytest.New(echosentrymiddleware.SentryConfig{
+		SpanMirror: NewSpanMirror(provider),
+	})
+	require.NoError(t, err)
+
+	harness.Echo.GET("/users/:id", func(c echo.Context) error {
+		require.True(t, trace.SpanContextFromContext(c.Request().Context()).IsValid())
+		return c.NoContent(http.StatusServiceUnavailable)
+	})
+
+	harness.DoRequest(httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /users/:id", spans[0].Name())
+	require.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	require.Contains(t, spans[0].Attributes(), attribute.String("http.route", "/users/:id"))
+	require.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", http.StatusServiceUnavailable))
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+}
@@ -0,0 +1,74 @@
+package echosentrymiddleware
+
+import (
+	"regexp"
+
+	"github.com/getsentry/sentry-go"
+)
+
+var (
+	piiJWTPattern  = `eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`
+	piiCardPattern = `\b\d(?:[ -]?\d){12,18}\b`
+
+	piiRe     = regexp.MustCompile(piiJWTPattern + `|` + emailPattern + `|` + piiCardPattern)
+	piiCardRe = regexp.MustCompile(`^` + piiCardPattern + `$`)
+)
+
+// maskPII masks emails, JWTs and card numbers (passing the Luhn check) found in the value,
+// it is a safety net for values not covered by explicit scrubbing rules
+func maskPII(value string) (string, bool) {
+	if !piiRe.MatchString(value) {
+		return value, false
+	}
+
+	masked := false
+	value = piiRe.ReplaceAllStringFunc(value, func(match string) string {
+		if piiCardRe.MatchString(match) && !isLuhnValid(match) {
+			return match
+		}
+
+		masked = true
+
+		return scrubbedValue
+	})
+
+	return value, masked
+}
+
+// isLuhnValid validates the checksum of card numbers, separators are ignored
+func isLuhnValid(number string) bool {
+	sum, double := 0, false
+
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			continue
+		}
+
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// detectPII masks PII in the dumped value with DetectPII and audits the masking
+func detectPII(span *sentry.Span, config SentryConfig, where, value string) string {
+	if !config.DetectPII {
+		return value
+	}
+
+	value, masked := maskPII(value)
+	if masked {
+		auditRedaction(span, where, "pii")
+	}
+
+	return value
+}
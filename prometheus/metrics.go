@@ -0,0 +1,77 @@
+// Package echosentryprometheus records metrics of the echo Sentry middleware internals with Prometheus,
+// to monitor the observability pipeline itself
+package echosentryprometheus
+
+import (
+	"time"
+
+	echosentrymiddleware "github.com/adlandh/echo-sentry-middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var _ echosentrymiddleware.Metrics = (*Metrics)(nil)
+
+// Metrics are Prometheus collectors of the middleware internals, set them as SentryConfig.Metrics
+type Metrics struct {
+	transactionsStarted prometheus.Counter
+	transactionsSampled prometheus.Counter
+	transactionsDropped prometheus.Counter
+	bytesDumped         *prometheus.CounterVec
+	scrubDuration       prometheus.Histogram
+}
+
+// NewMetrics creates the collectors and registers them with the registerer (nil skips registration)
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+
+	return &Metrics{
+		transactionsStarted: factory.NewCounter(prometheus.CounterOpts{
+			Name: "echo_sentry_transactions_started_total",
+			Help: "Number of transactions started by the middleware.",
+		}),
+		transactionsSampled: factory.NewCounter(prometheus.CounterOpts{
+			Name: "echo_sentry_transactions_sampled_total",
+			Help: "Number of finished transactions sent to Sentry.",
+		}),
+		transactionsDropped: factory.NewCounter(prometheus.CounterOpts{
+			Name: "echo_sentry_transactions_dropped_total",
+			Help: "Number of finished transactions dropped by sampling.",
+		}),
+		bytesDumped: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "echo_sentry_dumped_bytes_total",
+			Help: "Number of request and response body bytes dumped.",
+		}, []string{"kind"}),
+		scrubDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "echo_sentry_scrub_duration_seconds",
+			Help:    "Time spent scrubbing and recording dumped bodies.",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 8),
+		}),
+	}
+}
+
+// TransactionStarted implements echosentrymiddleware.Metrics
+func (m *Metrics) TransactionStarted() {
+	m.transactionsStarted.Inc()
+}
+
+// TransactionFinished implements echosentrymiddleware.Metrics
+func (m *Metrics) TransactionFinished(sampled bool) {
+	if sampled {
+		m.transactionsSampled.Inc()
+	} else {
+		m.transactionsDropped.Inc()
+	}
+}
+
+// BodyDumped implements echosentrymiddleware.Metrics
+func (m *Metrics) BodyDumped(kind string, size int) {
+	if size > 0 {
+		m.bytesDumped.WithLabelValues(kind).Add(float64(size))
+	}
+}
+
+// BodyScrubbed implements echosentrymiddleware.Metrics
+func (m *Metrics) BodyScrubbed(duration time.Duration) {
+	m.scrubDuration.Observe(duration.Seconds())
+}
@@ -0,0 +1,46 @@
+package echosentryprometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	echosentrymiddleware "github.com/adlandh/echo-sentry-middleware"
+	"github.com/adlandh/echo-sentry-middleware/sentrytest"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := NewMetrics(reg)
+
+	harness, err := sentrytest.New(echosentrymiddleware.SentryConfig{
+		IsBodyDump: true,
+		Metrics:    metrics,
+	})
+	require.NoError(t, err)
+
+	harness.Echo.POST("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "response")
+	})
+
+	for range 2 {
+		transaction, _ := harness.DoRequest(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("request")))
+		require.NotNil(t, transaction)
+	}
+
+	require.InDelta(t, 2, testutil.ToFloat64(metrics.transactionsStarted), 0)
+	require.InDelta(t, 2, testutil.ToFloat64(metrics.transactionsSampled), 0)
+	require.InDelta(t, 0, testutil.ToFloat64(metrics.transactionsDropped), 0)
+	require.InDelta(t, 2*len("request"), testutil.ToFloat64(metrics.bytesDumped.WithLabelValues("req")), 0)
+	require.InDelta(t, 2*len("response"), testutil.ToFloat64(metrics.bytesDumped.WithLabelValues("resp")), 0)
+	require.Equal(t, 1, testutil.CollectAndCount(metrics.scrubDuration))
+
+	count, err := testutil.GatherAndCount(reg)
+	require.NoError(t, err)
+	require.Equal(t, 6, count)
+}
@@ -0,0 +1,28 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// PropagationHeaders returns sentry-trace and baggage headers for the current span,
+// to be added to outgoing requests made by clients that can't take a custom RoundTripper.
+// The result is empty when the request is not traced by the middleware.
+func PropagationHeaders(c echo.Context) http.Header {
+	headers := make(http.Header)
+
+	span := sentry.SpanFromContext(c.Request().Context())
+	if span == nil {
+		return headers
+	}
+
+	headers.Set(sentry.SentryTraceHeader, span.ToSentryTrace())
+
+	if baggage := span.ToBaggage(); baggage != "" {
+		headers.Set(sentry.SentryBaggageHeader, baggage)
+	}
+
+	return headers
+}
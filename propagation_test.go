@@ -0,0 +1,37 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestPropagationHeaders() {
+	s.e.Use(Middleware())
+
+	var (
+		span    *sentry.Span
+		headers http.Header
+	)
+
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.SpanFromContext(c.Request().Context())
+		headers = PropagationHeaders(c)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+
+	s.NotNil(span)
+	s.Equal(span.ToSentryTrace(), headers.Get(sentry.SentryTraceHeader))
+	s.Contains(headers.Get(sentry.SentryBaggageHeader), "sentry-trace_id="+span.TraceID.String())
+
+	s.Run("without span", func() {
+		c := s.e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		s.Empty(PropagationHeaders(c))
+	})
+}
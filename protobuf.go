@@ -0,0 +1,38 @@
+package echosentrymiddleware
+
+import (
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+func isProtobufMediaType(mediaType string) bool {
+	switch mediaType {
+	case "application/x-protobuf", "application/protobuf", "application/grpc",
+		"application/grpc+proto", "application/grpc-web", "application/grpc-web+proto",
+		"application/grpc-web-text", "application/grpc-web-text+proto":
+		return true
+	}
+
+	return false
+}
+
+// getGRPCMethod splits a gRPC path like /package.Service/Method
+func getGRPCMethod(path string) (service, method string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !ok || service == "" || method == "" || strings.Contains(method, "/") {
+		return "", ""
+	}
+
+	return service, method
+}
+
+// dumpProtobufBody records only the size of a binary body, frames are never dumped
+func dumpProtobufBody(span *sentry.Span, config SentryConfig, prefix string, path string, size int) {
+	span.SetData(prefix+".body.size", size)
+
+	if service, method := getGRPCMethod(path); service != "" {
+		setTag(span, config, "grpc.service", service)
+		setTag(span, config, "grpc.method", method)
+	}
+}
@@ -0,0 +1,87 @@
+package echosentrymiddleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxForwardedChain limits the number of recorded X-Forwarded-For entries
+const maxForwardedChain = 10
+
+// parseTrustedProxies parses CIDRs or single IPs, it panics on invalid values like other echo middleware configs
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(proxies))
+
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			panic("echo: invalid trusted proxy " + proxy + ": " + err.Error())
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks
+}
+
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getForwardedChain returns X-Forwarded-For entries, the closest proxy is the last one
+func getForwardedChain(request *http.Request) []string {
+	var chain []string
+
+	for _, header := range request.Header.Values(echo.HeaderXForwardedFor) {
+		for _, ip := range strings.Split(header, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				chain = append(chain, ip)
+			}
+		}
+	}
+
+	return chain
+}
+
+// resolveClientIP walks the forwarding chain from the connection peer to the client,
+// skipping trusted proxies; the first untrusted address is the client
+func resolveClientIP(request *http.Request, trustedProxies []*net.IPNet) (string, []string) {
+	clientIP, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		clientIP = request.RemoteAddr
+	}
+
+	chain := getForwardedChain(request)
+
+	for i := len(chain) - 1; i >= 0 && isTrustedProxy(clientIP, trustedProxies); i-- {
+		clientIP = chain[i]
+	}
+
+	if len(chain) > maxForwardedChain {
+		chain = chain[len(chain)-maxForwardedChain:]
+	}
+
+	return clientIP, chain
+}
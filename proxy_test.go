@@ -0,0 +1,100 @@
+package echosentrymiddleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	trusted := parseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{
+			name:       "direct",
+			remoteAddr: "1.2.3.4:1234",
+			forwarded:  "5.6.7.8",
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  "9.9.9.9, 5.6.7.8, 192.168.1.1",
+			want:       "5.6.7.8",
+		},
+		{
+			name:       "only trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  "10.0.0.2",
+			want:       "10.0.0.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+
+			clientIP, chain := resolveClientIP(req, trusted)
+			require.Equal(t, tt.want, clientIP)
+			require.NotEmpty(t, chain)
+		})
+	}
+
+	require.Panics(t, func() { parseTrustedProxies([]string{"invalid"}) })
+}
+
+func (s *MiddlewareTestSuite) TestIPExtractor() {
+	s.Run("application extractor", func() {
+		e := echo.New()
+		e.IPExtractor = echo.ExtractIPDirect()
+		e.Use(Middleware())
+
+		var span *sentry.Span
+		e.GET("/", func(c echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "1.2.3.4:1234"
+		req.Header.Set(echo.HeaderXForwardedFor, "5.6.7.8")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		s.Equal("1.2.3.4", span.Tags["client_ip"])
+	})
+
+	s.Run("config extractor", func() {
+		var span *sentry.Span
+		handler := HTTPMiddlewareWithConfig(SentryConfig{
+			IPExtractor: echo.ExtractIPFromXFFHeader(echo.TrustIPRange(mustParseCIDR("1.2.3.0/24"))),
+		})(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			span = sentry.TransactionFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "1.2.3.4:1234"
+		req.Header.Set(echo.HeaderXForwardedFor, "5.6.7.8")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		s.Equal("5.6.7.8", span.Tags["client_ip"])
+	})
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+
+	return network
+}
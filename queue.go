@@ -0,0 +1,60 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// DefaultMaxQueueTime is the default longest load balancer queue time accepted, the headers can be sent
+// by clients as well
+const DefaultMaxQueueTime = time.Minute
+
+// queueStartHeaders are set by load balancers with the time the request was received
+var queueStartHeaders = []string{"X-Request-Start", "X-Queue-Start"}
+
+// parseQueueStart parses values like "t=1700000000.123", in seconds, milliseconds or microseconds
+func parseQueueStart(value string) (time.Time, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "t=")
+
+	ts, err := strconv.ParseFloat(value, 64)
+	if err != nil || ts <= 0 {
+		return time.Time{}, false
+	}
+
+	switch {
+	case ts > 1e15: // microseconds
+		return time.UnixMicro(int64(ts)), true
+	case ts > 1e12: // milliseconds
+		return time.UnixMilli(int64(ts)), true
+	default: // seconds
+		return time.UnixMicro(int64(ts * 1e6)), true
+	}
+}
+
+// dumpQueueTime records time spent in load balancer queue before the request was handled
+func dumpQueueTime(span *sentry.Span, config SentryConfig, header http.Header) {
+	for _, name := range queueStartHeaders {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+
+		start, ok := parseQueueStart(value)
+		if !ok || !start.Before(span.StartTime) || span.StartTime.Sub(start) > config.MaxQueueTime {
+			continue
+		}
+
+		span.SetData("http.queue_time_ms", span.StartTime.Sub(start).Milliseconds())
+
+		queueSpan := span.StartChild("http.queue", sentry.WithDescription(name), idOption(config.IDGenerator))
+		queueSpan.StartTime = start
+		queueSpan.EndTime = span.StartTime
+		queueSpan.Finish()
+
+		return
+	}
+}
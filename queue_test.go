@@ -0,0 +1,81 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQueueStart(t *testing.T) {
+	want := time.UnixMilli(1700000000123)
+
+	for _, value := range []string{"t=1700000000.123", "1700000000123", "t=1700000000123000"} {
+		got, ok := parseQueueStart(value)
+		require.True(t, ok, value)
+		require.Equal(t, want.UnixMilli(), got.UnixMilli(), value)
+	}
+
+	_, ok := parseQueueStart("invalid")
+	require.False(t, ok)
+}
+
+func (s *MiddlewareTestSuite) TestQueueTime() {
+	s.e.Use(Middleware())
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	req.Header.Set("X-Request-Start", "t="+strconv.FormatInt(time.Now().Add(-time.Second).UnixMicro(), 10))
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.GreaterOrEqual(span.Data["http.queue_time_ms"], int64(1000))
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+
+	var queueSpan *sentry.Span
+	for _, sp := range events[0].Spans {
+		if sp.Op == "http.queue" {
+			queueSpan = sp
+		}
+	}
+
+	s.NotNil(queueSpan)
+	s.Equal(span.StartTime, queueSpan.EndTime)
+}
+
+func (s *MiddlewareTestSuite) TestQueueTimeTooOld() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{MaxQueueTime: time.Second}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	req.Header.Set("X-Request-Start", "t="+strconv.FormatInt(time.Now().Add(-time.Hour).UnixMicro(), 10))
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Require().NotNil(span)
+	s.NotContains(span.Data, "http.queue_time_ms")
+
+	events := s.transport.Events()
+	s.Require().Len(events, 1)
+
+	for _, sp := range events[0].Spans {
+		s.NotEqual("http.queue", sp.Op)
+	}
+}
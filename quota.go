@@ -0,0 +1,121 @@
+package echosentrymiddleware
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/getsentry/sentry-go"
+	"golang.org/x/time/rate"
+)
+
+// maxQuotaRouteBuckets limits memory used by the per-route caps on high cardinality routes
+const maxQuotaRouteBuckets = 10000
+
+// TransactionQuota caps sampled transactions sent per second, globally and per route, so a traffic spike
+// can't exhaust the Sentry quota. Transactions over the cap are dropped, but still update local counters.
+type TransactionQuota struct {
+	global *rate.Limiter
+
+	routeLimit rate.Limit
+	routeBurst int
+
+	mu     sync.Mutex
+	routes map[string]*quotaBucket
+
+	dropped atomic.Uint64
+}
+
+type quotaBucket struct {
+	limiter *rate.Limiter
+	dropped uint64
+}
+
+// NewTransactionQuota returns a quota allowing perSecond transactions in total and routePerSecond transactions
+// per route, each with bursts of burst transactions. 0 disables the respective cap.
+func NewTransactionQuota(perSecond, routePerSecond float64, burst int) *TransactionQuota {
+	q := &TransactionQuota{
+		routeLimit: rate.Limit(routePerSecond),
+		routeBurst: burst,
+		routes:     make(map[string]*quotaBucket),
+	}
+
+	if perSecond > 0 {
+		q.global = rate.NewLimiter(rate.Limit(perSecond), burst)
+	}
+
+	return q
+}
+
+// Dropped returns the total number of transactions dropped over the quota
+func (q *TransactionQuota) Dropped() uint64 {
+	return q.dropped.Load()
+}
+
+// allow reports whether a transaction of the route may be sent, together with the number of transactions
+// of the route dropped since the previous allowed one.
+func (q *TransactionQuota) allow(route string) (bool, uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var bucket *quotaBucket
+
+	if q.routeLimit > 0 {
+		var ok bool
+
+		bucket, ok = q.routes[route]
+		if !ok {
+			if len(q.routes) >= maxQuotaRouteBuckets {
+				clear(q.routes)
+			}
+
+			bucket = &quotaBucket{limiter: rate.NewLimiter(q.routeLimit, q.routeBurst)}
+			q.routes[route] = bucket
+		}
+
+		if !bucket.limiter.Allow() {
+			bucket.dropped++
+			q.dropped.Add(1)
+
+			return false, 0
+		}
+	}
+
+	if q.global != nil && !q.global.Allow() {
+		if bucket != nil {
+			bucket.dropped++
+		}
+
+		q.dropped.Add(1)
+
+		return false, 0
+	}
+
+	if bucket == nil {
+		return true, 0
+	}
+
+	dropped := bucket.dropped
+	bucket.dropped = 0
+
+	return true, dropped
+}
+
+// applyTransactionQuota drops sampled transactions over the quota,
+// sent transactions record how many of the route were dropped before them
+func applyTransactionQuota(config SentryConfig, span *sentry.Span, route string) {
+	if config.TransactionQuota == nil || !span.IsTransaction() || !span.Sampled.Bool() {
+		return
+	}
+
+	ok, dropped := config.TransactionQuota.allow(route)
+	if !ok {
+		span.Sampled = sentry.SampledFalse
+		stats.transactionsOverQuota.Add(1)
+
+		return
+	}
+
+	if dropped > 0 {
+		span.SetData("quota.dropped", dropped)
+	}
+}
@@ -0,0 +1,76 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestTransactionQuota(t *testing.T) {
+	t.Run("per route", func(t *testing.T) {
+		quota := NewTransactionQuota(0, 0.001, 1)
+
+		ok, dropped := quota.allow("/a")
+		require.True(t, ok)
+		require.Zero(t, dropped)
+
+		ok, _ = quota.allow("/a")
+		require.False(t, ok)
+
+		ok, _ = quota.allow("/b")
+		require.True(t, ok)
+
+		require.Equal(t, uint64(1), quota.Dropped())
+	})
+
+	t.Run("global", func(t *testing.T) {
+		quota := NewTransactionQuota(0.001, 0, 2)
+
+		for _, route := range []string{"/a", "/b"} {
+			ok, _ := quota.allow(route)
+			require.True(t, ok)
+		}
+
+		ok, _ := quota.allow("/c")
+		require.False(t, ok)
+		require.Equal(t, uint64(1), quota.Dropped())
+	})
+
+	t.Run("dropped since last allowed", func(t *testing.T) {
+		quota := NewTransactionQuota(0, 0.001, 1)
+		quota.allow("/a")
+		quota.allow("/a")
+		quota.allow("/a")
+
+		quota.routes["/a"].limiter.SetLimit(rate.Inf)
+
+		ok, dropped := quota.allow("/a")
+		require.True(t, ok)
+		require.Equal(t, uint64(2), dropped)
+	})
+}
+
+func (s *MiddlewareTestSuite) TestTransactionQuota() {
+	before := CurrentStats().TransactionsOverQuota
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		TransactionQuota: NewTransactionQuota(0, 0.001, 1),
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	s.Len(s.transport.Events(), 1)
+	s.Equal(int64(2), CurrentStats().TransactionsOverQuota-before)
+}
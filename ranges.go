@@ -0,0 +1,86 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerRange        = "Range"
+	headerContentRange = "Content-Range"
+)
+
+// contentRange is a parsed Content-Range header, Total is -1 for unknown lengths ("*")
+type contentRange struct {
+	Unit  string
+	Start int64
+	End   int64
+	Total int64
+}
+
+// parseContentRange parses "bytes 0-1023/4096" and "bytes 0-1023/*" Content-Range values
+func parseContentRange(value string) (contentRange, bool) {
+	unit, rest, ok := strings.Cut(value, " ")
+	if !ok {
+		return contentRange{}, false
+	}
+
+	bounds, total, ok := strings.Cut(rest, "/")
+	if !ok {
+		return contentRange{}, false
+	}
+
+	start, end, ok := strings.Cut(bounds, "-")
+	if !ok {
+		return contentRange{}, false
+	}
+
+	r := contentRange{Unit: unit, Total: -1}
+
+	var err error
+
+	if r.Start, err = strconv.ParseInt(start, 10, 64); err != nil {
+		return contentRange{}, false
+	}
+
+	if r.End, err = strconv.ParseInt(end, 10, 64); err != nil {
+		return contentRange{}, false
+	}
+
+	if total != "*" {
+		if r.Total, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return contentRange{}, false
+		}
+	}
+
+	return r, true
+}
+
+// dumpRanges records the requested ranges and the range of partial responses as span data,
+// even without headers dumping, to debug resumable downloads
+func dumpRanges(c echo.Context, span *sentry.Span, status int) {
+	requested := c.Request().Header.Get(headerRange)
+	if requested != "" {
+		span.SetData("http.request.range", requested)
+	}
+
+	if status != http.StatusPartialContent {
+		return
+	}
+
+	r, ok := parseContentRange(c.Response().Header().Get(headerContentRange))
+	if !ok {
+		return
+	}
+
+	span.SetData("http.response.range", map[string]interface{}{
+		"unit":  r.Unit,
+		"start": r.Start,
+		"end":   r.End,
+		"total": r.Total,
+	})
+}
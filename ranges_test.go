@@ -0,0 +1,65 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		value string
+		want  contentRange
+		ok    bool
+	}{
+		{value: "bytes 0-1023/4096", want: contentRange{Unit: "bytes", Start: 0, End: 1023, Total: 4096}, ok: true},
+		{value: "bytes 10-19/*", want: contentRange{Unit: "bytes", Start: 10, End: 19, Total: -1}, ok: true},
+		{value: "bytes */4096"},
+		{value: "bytes 0-1023"},
+		{value: "garbage"},
+		{value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseContentRange(tt.value)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (s *MiddlewareTestSuite) TestRanges() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{AreHeadersDump: false}))
+
+	var span *sentry.Span
+	s.e.GET("/file", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		http.ServeContent(c.Response(), c.Request(), "file.bin", time.Time{}, bytes.NewReader(make([]byte, 100)))
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/file", nil)
+	req.Header.Set(headerRange, "bytes=10-19")
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+
+	s.Equal(http.StatusPartialContent, rec.Code)
+	s.Equal("bytes=10-19", span.Data["http.request.range"])
+	s.Equal(map[string]interface{}{
+		"unit":  "bytes",
+		"start": int64(10),
+		"end":   int64(19),
+		"total": int64(100),
+	}, span.Data["http.response.range"])
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/file", nil))
+	s.NotContains(span.Data, "http.request.range")
+	s.NotContains(span.Data, "http.response.range")
+}
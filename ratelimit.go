@@ -0,0 +1,74 @@
+package echosentrymiddleware
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+// maxErrorLimiterBuckets limits memory used by the limiter on high cardinality errors
+const maxErrorLimiterBuckets = 10000
+
+// ErrorLimiter is a token bucket limiter for error events captured by the middleware.
+// Buckets are kept per route and error fingerprint.
+type ErrorLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu      sync.Mutex
+	buckets map[string]*errorBucket
+
+	suppressed atomic.Uint64
+}
+
+type errorBucket struct {
+	limiter    *rate.Limiter
+	suppressed uint64
+}
+
+// NewErrorLimiter returns a limiter allowing eventsPerSecond events with bursts of burst events
+// for each route and error fingerprint.
+func NewErrorLimiter(eventsPerSecond float64, burst int) *ErrorLimiter {
+	return &ErrorLimiter{
+		limit:   rate.Limit(eventsPerSecond),
+		burst:   burst,
+		buckets: make(map[string]*errorBucket),
+	}
+}
+
+// Suppressed returns the total number of events suppressed by the limiter
+func (l *ErrorLimiter) Suppressed() uint64 {
+	return l.suppressed.Load()
+}
+
+// allow reports whether an event is allowed, together with the number of events
+// suppressed for the same key since the previous allowed one.
+func (l *ErrorLimiter) allow(route, fingerprint string) (bool, uint64) {
+	key := route + "\x00" + fingerprint
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxErrorLimiterBuckets {
+			clear(l.buckets)
+		}
+
+		bucket = &errorBucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = bucket
+	}
+
+	if !bucket.limiter.Allow() {
+		bucket.suppressed++
+		l.suppressed.Add(1)
+
+		return false, 0
+	}
+
+	suppressed := bucket.suppressed
+	bucket.suppressed = 0
+
+	return true, suppressed
+}
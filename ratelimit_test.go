@@ -0,0 +1,28 @@
+package echosentrymiddleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorLimiter(t *testing.T) {
+	limiter := NewErrorLimiter(0.001, 2)
+
+	for range 2 {
+		ok, suppressed := limiter.allow("/", "error")
+		require.True(t, ok)
+		require.Zero(t, suppressed)
+	}
+
+	ok, _ := limiter.allow("/", "error")
+	require.False(t, ok)
+
+	ok, _ = limiter.allow("/", "another error")
+	require.True(t, ok)
+
+	ok, _ = limiter.allow("/other", "error")
+	require.True(t, ok)
+
+	require.EqualValues(t, 1, limiter.Suppressed())
+}
@@ -0,0 +1,42 @@
+package echosentrymiddleware
+
+import (
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// dumpRedirect records the scrubbed Location of 3xx responses and adds a breadcrumb about the redirect,
+// so redirect loops can be followed in Sentry
+func dumpRedirect(c echo.Context, config SentryConfig, span *sentry.Span, status int) {
+	if status < 300 || status > 399 {
+		return
+	}
+
+	location := c.Response().Header().Get(echo.HeaderLocation)
+	if location == "" {
+		return
+	}
+
+	if scrubbed := config.URLScrubber(location); scrubbed != location {
+		location = scrubbed
+		auditRedaction(span, "redirect", "location")
+	}
+
+	setTag(span, config, "redirect.location", location)
+
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil {
+		return
+	}
+
+	hub.AddBreadcrumb(&sentry.Breadcrumb{
+		Type:     "navigation",
+		Category: "redirect",
+		Data: map[string]interface{}{
+			"from":        config.URLScrubber(c.Request().RequestURI),
+			"to":          location,
+			"status_code": status,
+		},
+		Level: sentry.LevelInfo,
+	}, nil)
+}
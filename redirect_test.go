@@ -0,0 +1,33 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestRedirect() {
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureEventOnStatus: []StatusRange{{From: http.StatusFound, To: http.StatusFound}},
+	}))
+	s.e.GET("/login", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.Redirect(http.StatusFound, "/callback?token=secret")
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	s.NotNil(span)
+	s.Equal("/callback?token="+scrubbedValue, span.Tags["redirect.location"])
+
+	events := s.errorEvents()
+	s.Len(events, 1)
+	s.Len(events[0].Breadcrumbs, 1)
+	s.Equal("redirect", events[0].Breadcrumbs[0].Category)
+	s.Equal("/login", events[0].Breadcrumbs[0].Data["from"])
+	s.Equal("/callback?token="+scrubbedValue, events[0].Breadcrumbs[0].Data["to"])
+}
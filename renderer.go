@@ -0,0 +1,54 @@
+package echosentrymiddleware
+
+import (
+	"io"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+type renderer struct {
+	renderer echo.Renderer
+}
+
+// WrapRenderer wraps an echo.Renderer, so every c.Render call gets a child span
+// with the template name and the number of written bytes
+func WrapRenderer(r echo.Renderer) echo.Renderer {
+	return &renderer{renderer: r}
+}
+
+func (r *renderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	if sentry.SpanFromContext(c.Request().Context()) == nil {
+		return r.renderer.Render(w, name, data, c)
+	}
+
+	span, finish := startHandledSpan(c.Request().Context(), "template.render", name)
+	defer finish()
+
+	cw := &countingWriter{Writer: w}
+
+	err := r.renderer.Render(cw, name, data, c)
+	if err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		span.SetData("error", err.Error())
+	} else {
+		span.Status = sentry.SpanStatusOK
+	}
+
+	span.SetData("bytes", cw.n)
+
+	return err
+}
+
+// countingWriter counts bytes written to the underlying writer
+type countingWriter struct {
+	io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.Writer.Write(b)
+	w.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,50 @@
+package echosentrymiddleware
+
+import (
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+type templateRenderer struct {
+	templates *template.Template
+}
+
+func (t *templateRenderer) Render(w io.Writer, name string, data interface{}, _ echo.Context) error {
+	return t.templates.ExecuteTemplate(w, name, data)
+}
+
+func (s *MiddlewareTestSuite) TestWrapRenderer() {
+	s.e.Renderer = WrapRenderer(&templateRenderer{
+		templates: template.Must(template.New("hello").Parse("Hello, {{.}}!")),
+	})
+	s.e.Use(Middleware())
+	s.e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "hello", "World")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	s.Equal("Hello, World!", rec.Body.String())
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+
+	var span *sentry.Span
+	for _, sp := range events[0].Spans {
+		if sp.Op == "template.render" {
+			span = sp
+		}
+	}
+
+	s.NotNil(span)
+	s.Equal("hello", span.Description)
+	s.EqualValues(13, span.Data["bytes"])
+	s.Equal(sentry.SpanStatusOK, span.Status)
+}
@@ -0,0 +1,26 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// trackResponseWrites records the time from the start of the request to the last write of the response body
+func trackResponseWrites(c echo.Context, config SentryConfig, span *sentry.Span) {
+	c.Response().After(func() {
+		span.SetData("http.response.written_ms", config.Clock().Sub(span.StartTime).Milliseconds())
+	})
+}
+
+// flushResponse writes the buffered rest of the response body to the client,
+// so the span end time includes it. Uncommitted responses are left to echo.
+func flushResponse(c echo.Context) {
+	if !c.Response().Committed {
+		return
+	}
+
+	// writers without flushing and gone clients only mean there is nothing more to wait for
+	_ = http.NewResponseController(c.Response().Writer).Flush()
+}
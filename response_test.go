@@ -0,0 +1,65 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+type slowFlushRecorder struct {
+	*httptest.ResponseRecorder
+	flush func()
+}
+
+func (r *slowFlushRecorder) Flush() {
+	r.flush()
+	r.ResponseRecorder.Flush()
+}
+
+func (s *MiddlewareTestSuite) TestFinishAfterResponse() {
+	now := time.Now()
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		FinishAfterResponse: true,
+		Clock: func() time.Time {
+			return now
+		},
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		now = now.Add(10 * time.Millisecond)
+		c.Response().WriteHeader(http.StatusOK)
+		now = now.Add(20 * time.Millisecond)
+		_, err := c.Response().Write([]byte("large body"))
+		return err
+	})
+
+	rec := &slowFlushRecorder{
+		ResponseRecorder: httptest.NewRecorder(),
+		flush: func() {
+			now = now.Add(30 * time.Millisecond)
+		},
+	}
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.True(rec.Flushed)
+	s.Equal(int64(30), span.Data["http.response.written_ms"])
+	s.Equal(60*time.Millisecond, span.EndTime.Sub(span.StartTime))
+}
+
+func (s *MiddlewareTestSuite) TestFinishAfterResponseUncommitted() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{FinishAfterResponse: true}))
+	s.e.GET("/", func(echo.Context) error {
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.False(rec.Flushed)
+}
@@ -0,0 +1,22 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// retryHeaders are recorded even without headers dumping to debug retry storms
+var retryHeaders = map[string]string{
+	"Idempotency-Key": "idempotency_key",
+	"X-Retry-Count":   "retry_count",
+	"Retry-After":     "retry_after",
+}
+
+func dumpRetryHeaders(span *sentry.Span, prefix string, header http.Header) {
+	for name, key := range retryHeaders {
+		if value := header.Get(name); value != "" {
+			span.SetData(prefix+"."+key, value)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestRetryHeaders() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{AreHeadersDump: false}))
+
+	var span *sentry.Span
+	s.e.POST("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		c.Response().Header().Set("Retry-After", "120")
+		return c.NoContent(http.StatusServiceUnavailable)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Idempotency-Key", "abc")
+	req.Header.Set("X-Retry-Count", "3")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Equal("abc", span.Data["req.idempotency_key"])
+	s.Equal("3", span.Data["req.retry_count"])
+	s.Equal("120", span.Data["resp.retry_after"])
+	s.NotContains(span.Data, "req.retry_after")
+	s.Empty(span.Tags["req.header.Idempotency-Key"])
+}
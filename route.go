@@ -0,0 +1,130 @@
+package echosentrymiddleware
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// defaultRouteNameRe matches closures like main.main.func1, echo names routes after their handlers by default
+var defaultRouteNameRe = regexp.MustCompile(`\.func\d+$`)
+
+// isExplicitRouteName reports whether the route name was set by the application,
+// echo uses the handler function name (e.g. github.com/org/app/handlers.Create) otherwise
+func isExplicitRouteName(name string) bool {
+	return name != "" &&
+		!strings.ContainsAny(name, "/()") &&
+		!strings.HasPrefix(name, "main.") &&
+		!defaultRouteNameRe.MatchString(name)
+}
+
+// routeNames caches explicit names of echo routes by method and path. Only registered routes are cached,
+// paths of unrouted requests would grow the cache without bound.
+type routeNames struct {
+	names sync.Map
+}
+
+func (r *routeNames) get(c echo.Context) string {
+	key := c.Request().Method + " " + c.Path()
+	if name, ok := r.names.Load(key); ok {
+		return name.(string)
+	}
+
+	var (
+		name       string
+		registered bool
+	)
+
+	for _, route := range c.Echo().Routes() {
+		if route.Method != c.Request().Method || route.Path != c.Path() {
+			continue
+		}
+
+		registered = true
+
+		if isExplicitRouteName(route.Name) {
+			name = route.Name
+			break
+		}
+	}
+
+	if registered {
+		r.names.Store(key, name)
+	}
+
+	return name
+}
+
+// opNamePlaceholders are placeholders of OpNameTemplate
+var opNamePlaceholders = []string{"{method}", "{route}", "{route_name}", "{service}"}
+
+// opNameTemplate is OpNameTemplate split into literal parts and placeholders once, so it is rendered
+// without a replacer per request
+type opNameTemplate []string
+
+func parseOpNameTemplate(template string) opNameTemplate {
+	var parts opNameTemplate
+
+	for template != "" {
+		next, placeholder := len(template), ""
+
+		for _, p := range opNamePlaceholders {
+			if i := strings.Index(template, p); i >= 0 && i < next {
+				next, placeholder = i, p
+			}
+		}
+
+		if next > 0 {
+			parts = append(parts, template[:next])
+		}
+
+		if placeholder == "" {
+			break
+		}
+
+		parts = append(parts, placeholder)
+		template = template[next+len(placeholder):]
+	}
+
+	return parts
+}
+
+// formatOpName renders OpNameTemplate, placeholders are {method}, {route}, {route_name} (falls back to the route)
+// and {service}
+func formatOpName(config SentryConfig, c echo.Context, routeName string) string {
+	if routeName == "" {
+		routeName = c.Path()
+	}
+
+	var b strings.Builder
+
+	b.Grow(len(config.OpNameTemplate) + len(c.Request().Method) + 2*len(c.Path()) + len(config.ServiceName))
+
+	for _, part := range config.opNameTemplate {
+		switch part {
+		case "{method}":
+			b.WriteString(c.Request().Method)
+		case "{route}":
+			b.WriteString(c.Path())
+		case "{route_name}":
+			b.WriteString(routeName)
+		case "{service}":
+			b.WriteString(config.ServiceName)
+		default:
+			b.WriteString(part)
+		}
+	}
+
+	return b.String()
+}
+
+// getRoute returns the route of the request or its scrubbed path when there is no route
+func getRoute(c echo.Context, config SentryConfig) string {
+	if route := c.Path(); route != "" {
+		return route
+	}
+
+	return config.URLScrubber(c.Request().URL.Path)
+}
@@ -0,0 +1,132 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsExplicitRouteName(t *testing.T) {
+	require.True(t, isExplicitRouteName("checkout.create"))
+	require.True(t, isExplicitRouteName("users"))
+	require.False(t, isExplicitRouteName(""))
+	require.False(t, isExplicitRouteName("main.handler"))
+	require.False(t, isExplicitRouteName("github.com/org/app/handlers.Create"))
+	require.False(t, isExplicitRouteName("handlers.(*Server).Create-fm"))
+	require.False(t, isExplicitRouteName("app.Routes.func1"))
+}
+
+type routeNameServer struct{}
+
+func (routeNameServer) Get(echo.Context) error { return nil }
+
+func TestIsExplicitRouteNameDefaults(t *testing.T) {
+	e := echo.New()
+	filesystem := os.DirFS(t.TempDir())
+
+	routes := []*echo.Route{
+		e.GET("/closure", func(echo.Context) error { return nil }),
+		e.GET("/method", routeNameServer{}.Get),
+		e.GET("/func", echo.NotFoundHandler),
+		e.StaticFS("/assets", filesystem),
+		e.File("/file", "file"),
+	}
+
+	for _, route := range routes {
+		require.False(t, isExplicitRouteName(route.Name), route.Name)
+	}
+
+	named := e.GET("/named", func(echo.Context) error { return nil })
+	named.Name = "users.get"
+	require.True(t, isExplicitRouteName(named.Name))
+}
+
+func TestRouteNamesCache(t *testing.T) {
+	e := echo.New()
+	e.GET("/users", func(echo.Context) error { return nil }).Name = "users.list"
+
+	names := &routeNames{}
+
+	for _, path := range []string{"/users", "/unrouted/1", "/unrouted/2"} {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, path, nil), httptest.NewRecorder())
+		c.SetPath(path)
+		names.get(c)
+	}
+
+	var cached []string
+
+	names.names.Range(func(key, _ any) bool {
+		cached = append(cached, key.(string))
+		return true
+	})
+
+	require.Equal(t, []string{"GET /users"}, cached)
+}
+
+func TestParseOpNameTemplate(t *testing.T) {
+	require.Equal(t, opNameTemplate{"HTTP ", "{method}", " ", "{route}"}, parseOpNameTemplate(DefaultOpNameTemplate))
+	require.Equal(t, opNameTemplate{"{service}", ".", "{route_name}"}, parseOpNameTemplate("{service}.{route_name}"))
+	require.Equal(t, opNameTemplate{"static {unknown}"}, parseOpNameTemplate("static {unknown}"))
+	require.Empty(t, parseOpNameTemplate(""))
+}
+
+func (s *MiddlewareTestSuite) TestRouteNames() {
+	tests := []struct {
+		name          string
+		config        SentryConfig
+		postName      string
+		postSource    sentry.TransactionSource
+		postOp, getOp string
+		postRouteName string
+	}{
+		{
+			name:          "route name",
+			config:        SentryConfig{UseRouteName: true},
+			postName:      "checkout.create",
+			postSource:    sentry.SourceCustom,
+			postOp:        "HTTP POST /checkout",
+			postRouteName: "checkout.create",
+			getOp:         "HTTP GET /checkout",
+		},
+		{
+			name:          "op name template",
+			config:        SentryConfig{OpNameTemplate: "{service}.{route_name} ({method} {route})", ServiceName: "shop"},
+			postName:      "HTTP POST /checkout",
+			postSource:    sentry.SourceURL,
+			postOp:        "shop.checkout.create (POST /checkout)",
+			postRouteName: "checkout.create",
+			getOp:         "shop./checkout (GET /checkout)",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var span *sentry.Span
+
+			e := echo.New()
+			e.Use(MiddlewareWithConfig(tt.config))
+			handler := func(c echo.Context) error {
+				span = sentry.TransactionFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}
+			e.POST("/checkout", handler).Name = "checkout.create"
+			e.GET("/checkout", handler)
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/checkout", nil))
+			s.Equal(tt.postName, span.Name)
+			s.Equal(tt.postSource, span.Source)
+			s.Equal(tt.postOp, span.Op)
+			s.Equal(tt.postRouteName, span.Tags["route.name"])
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/checkout", nil))
+			s.Equal("HTTP GET /checkout", span.Name)
+			s.Equal(sentry.SourceURL, span.Source)
+			s.Equal(tt.getOp, span.Op)
+		})
+	}
+}
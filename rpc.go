@@ -0,0 +1,131 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerConnectProtocolVersion = "Connect-Protocol-Version"
+	headerGRPCStatus             = "Grpc-Status"
+	// headerGRPCMetadataPrefix prefixes metadata forwarded as headers by grpc-gateway
+	headerGRPCMetadataPrefix = "Grpc-Metadata-"
+)
+
+// grpcSpanStatuses maps gRPC status codes to span statuses
+var grpcSpanStatuses = []sentry.SpanStatus{
+	sentry.SpanStatusOK,
+	sentry.SpanStatusCanceled,
+	sentry.SpanStatusUnknown,
+	sentry.SpanStatusInvalidArgument,
+	sentry.SpanStatusDeadlineExceeded,
+	sentry.SpanStatusNotFound,
+	sentry.SpanStatusAlreadyExists,
+	sentry.SpanStatusPermissionDenied,
+	sentry.SpanStatusResourceExhausted,
+	sentry.SpanStatusFailedPrecondition,
+	sentry.SpanStatusAborted,
+	sentry.SpanStatusOutOfRange,
+	sentry.SpanStatusUnimplemented,
+	sentry.SpanStatusInternalError,
+	sentry.SpanStatusUnavailable,
+	sentry.SpanStatusDataLoss,
+	sentry.SpanStatusUnauthenticated,
+}
+
+// getRPCProtocol detects RPC-over-HTTP requests by their content type and headers,
+// it returns "grpc", "grpc-web", "connect" or an empty string
+func getRPCProtocol(request *http.Request) string {
+	mediaType := getMediaType(request.Header)
+
+	switch {
+	case strings.HasPrefix(mediaType, "application/grpc-web"):
+		return "grpc-web"
+	case strings.HasPrefix(mediaType, "application/grpc"):
+		return "grpc"
+	case strings.HasPrefix(mediaType, "application/connect+"),
+		request.Header.Get(headerConnectProtocolVersion) != "",
+		// unary Connect calls with GET carry the protocol version in the query
+		request.Method == http.MethodGet && strings.Contains(request.URL.RawQuery, "connect=v"):
+		return "connect"
+	}
+
+	return ""
+}
+
+// getRPCName returns the transaction name of RPC requests to one of RPCRoutes, "package.Service/Method"
+func getRPCName(c echo.Context, config SentryConfig, request *http.Request) string {
+	if !slices.Contains(config.RPCRoutes, c.Path()) || getRPCProtocol(request) == "" {
+		return ""
+	}
+
+	service, method := getGRPCMethod(request.URL.Path)
+	if service == "" {
+		return ""
+	}
+
+	return service + "/" + method
+}
+
+// isGRPCGatewayResponse reports whether the response carries metadata forwarded by grpc-gateway
+func isGRPCGatewayResponse(header http.Header) bool {
+	for name := range header {
+		if strings.HasPrefix(name, headerGRPCMetadataPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getGRPCStatus returns the gRPC status code of the response headers or trailers
+func getGRPCStatus(header http.Header) (int, bool) {
+	value := header.Get(headerGRPCStatus)
+	if value == "" {
+		value = header.Get(http.TrailerPrefix + headerGRPCStatus)
+	}
+
+	code, err := strconv.Atoi(value)
+	if err != nil || code < 0 || code >= len(grpcSpanStatuses) {
+		return 0, false
+	}
+
+	return code, true
+}
+
+// dumpRPC tags the RPC protocol, service and method and maps the grpc-status of the response to the span status,
+// gRPC errors are usually sent with the 200 status code. The protocol of the request is only detected on RPCRoutes.
+func dumpRPC(c echo.Context, config SentryConfig, span *sentry.Span) {
+	request := c.Request()
+	header := c.Response().Header()
+
+	var protocol string
+	if slices.Contains(config.RPCRoutes, c.Path()) {
+		protocol = getRPCProtocol(request)
+	}
+
+	if protocol == "" && isGRPCGatewayResponse(header) {
+		protocol = "grpc-gateway"
+	}
+
+	if protocol == "" {
+		return
+	}
+
+	setTag(span, config, "rpc.protocol", protocol)
+
+	if service, method := getGRPCMethod(request.URL.Path); service != "" && protocol != "grpc-gateway" {
+		setTag(span, config, "grpc.service", service)
+		setTag(span, config, "grpc.method", method)
+	}
+
+	if code, ok := getGRPCStatus(header); ok {
+		setTag(span, config, "grpc.status", strconv.Itoa(code))
+		span.Status = grpcSpanStatuses[code]
+	}
+}
@@ -0,0 +1,106 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRPCProtocol(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header map[string]string
+		want   string
+	}{
+		{name: "grpc", header: map[string]string{echo.HeaderContentType: "application/grpc+proto"}, want: "grpc"},
+		{name: "grpc-web", header: map[string]string{echo.HeaderContentType: "application/grpc-web-text"}, want: "grpc-web"},
+		{name: "connect stream", header: map[string]string{echo.HeaderContentType: "application/connect+json"}, want: "connect"},
+		{
+			name:   "connect unary",
+			header: map[string]string{echo.HeaderContentType: echo.MIMEApplicationJSON, headerConnectProtocolVersion: "1"},
+			want:   "connect",
+		},
+		{name: "connect get", method: http.MethodGet, target: "/pkg.Users/Get?connect=v1&encoding=json", want: "connect"},
+		{name: "json", header: map[string]string{echo.HeaderContentType: echo.MIMEApplicationJSON}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, target := tt.method, tt.target
+			if method == "" {
+				method, target = http.MethodPost, "/pkg.Users/Get"
+			}
+
+			req := httptest.NewRequest(method, target, nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+
+			require.Equal(t, tt.want, getRPCProtocol(req))
+		})
+	}
+}
+
+func (s *MiddlewareTestSuite) TestRPC() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{RPCRoutes: []string{"/pkg.Users/*"}}))
+	s.e.Any("/pkg.Users/*", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "application/grpc-web+proto")
+		c.Response().Header().Set(headerGRPCStatus, "5")
+		return c.NoContent(http.StatusOK)
+	})
+	s.e.GET("/v1/users/:id", func(c echo.Context) error {
+		c.Response().Header().Set(headerGRPCMetadataPrefix+"Trace", "abc")
+		return c.JSON(http.StatusNotFound, map[string]interface{}{"code": 5})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/pkg.Users/Get", strings.NewReader(""))
+	req.Header.Set(echo.HeaderContentType, "application/grpc-web+proto")
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Require().Len(events, 1)
+	s.Equal("pkg.Users/Get", events[0].Transaction)
+	s.Equal(sentry.SourceRoute, events[0].TransactionInfo.Source)
+	s.Equal("grpc-web", events[0].Tags["rpc.protocol"])
+	s.Equal("pkg.Users", events[0].Tags["grpc.service"])
+	s.Equal("Get", events[0].Tags["grpc.method"])
+	s.Equal("5", events[0].Tags["grpc.status"])
+	s.Equal(sentry.SpanStatusNotFound, events[0].Contexts["trace"]["status"])
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/users/42", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events = s.transport.Events()
+	s.Require().Len(events, 2)
+	s.Equal("grpc-gateway", events[1].Tags["rpc.protocol"])
+	s.NotContains(events[1].Tags, "grpc.method")
+}
+
+func (s *MiddlewareTestSuite) TestRPCNotRPCRoute() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{RPCRoutes: []string{"/pkg.Users/*"}}))
+	s.e.POST("/:resource/:action", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/create?connect=v1", strings.NewReader(""))
+	req.Header.Set(echo.HeaderContentType, "application/grpc")
+	req.Header.Set(headerConnectProtocolVersion, "1")
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Require().Len(events, 1)
+	s.Equal("HTTP POST /orders/create?connect=v1", events[0].Transaction)
+	s.Equal(sentry.SourceURL, events[0].TransactionInfo.Source)
+	s.NotContains(events[0].Tags, "rpc.protocol")
+	s.NotContains(events[0].Tags, "grpc.method")
+}
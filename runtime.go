@@ -0,0 +1,47 @@
+package echosentrymiddleware
+
+import (
+	"runtime/metrics"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// runtimeMetrics are read with runtime/metrics, which unlike ReadMemStats doesn't stop the world
+var runtimeMetrics = [...]string{
+	"/sched/goroutines:goroutines",
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/heap/unused:bytes",
+	"/gc/heap/goal:bytes",
+	"/gc/cycles/total:gc-cycles",
+}
+
+// dumpSlowRequest attaches runtime stats to requests slower than the threshold,
+// to distinguish app slowness from runtime pressure
+func dumpSlowRequest(config SentryConfig, span *sentry.Span, duration time.Duration) {
+	if config.SlowRequestThreshold <= 0 || duration < config.SlowRequestThreshold {
+		return
+	}
+
+	setTag(span, config, "slow", "true")
+
+	var samples [len(runtimeMetrics)]metrics.Sample
+	for i, name := range runtimeMetrics {
+		samples[i].Name = name
+	}
+
+	metrics.Read(samples[:])
+
+	span.SetData("runtime.goroutines", uint64Sample(samples[0]))
+	span.SetData("runtime.heap_inuse", uint64Sample(samples[1])+uint64Sample(samples[2]))
+	span.SetData("runtime.heap_goal", uint64Sample(samples[3]))
+	span.SetData("runtime.gc_count", uint64Sample(samples[4]))
+}
+
+func uint64Sample(sample metrics.Sample) uint64 {
+	if sample.Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return sample.Value.Uint64()
+}
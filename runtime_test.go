@@ -0,0 +1,40 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestSlowRequest() {
+	now := time.Now()
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		SlowRequestThreshold: 10 * time.Millisecond,
+		Clock: func() time.Time {
+			return now
+		},
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/:delay", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		delay, _ := time.ParseDuration(c.Param("delay"))
+		now = now.Add(delay)
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/0s", nil))
+	s.Empty(span.Tags["slow"])
+	s.NotContains(span.Data, "runtime.goroutines")
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/20ms", nil))
+	s.Equal("true", span.Tags["slow"])
+	s.Positive(span.Data["runtime.goroutines"])
+	s.Positive(span.Data["runtime.heap_inuse"])
+	s.Positive(span.Data["runtime.heap_goal"])
+	s.Equal(20*time.Millisecond, span.EndTime.Sub(span.StartTime))
+}
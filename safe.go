@@ -0,0 +1,32 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrInternal wraps failures of the middleware itself, reported to OnInternalError
+var ErrInternal = errors.New("echosentrymiddleware: internal error")
+
+// safely runs telemetry code, so its failures degrade to missing telemetry instead of failed requests
+func safely(c echo.Context, config SentryConfig, stage string, fn func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+
+			reportInternalError(c, config, fmt.Errorf("%w: %s: panic: %v", ErrInternal, stage, r))
+		}
+	}()
+
+	fn()
+
+	return true
+}
+
+func reportInternalError(c echo.Context, config SentryConfig, err error) {
+	if config.OnInternalError != nil {
+		config.OnInternalError(err, c)
+	}
+}
@@ -0,0 +1,68 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestInternalErrors() {
+	var internalErrors []error
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IsBodyDump: true,
+		BodySkipper: func(echo.Context) (bool, bool) {
+			panic("body skipper failure")
+		},
+		OnInternalError: func(err error, _ echo.Context) {
+			internalErrors = append(internalErrors, err)
+		},
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("test", rec.Body.String())
+	s.Len(internalErrors, 2) // request dump panics and response dump fails without dumper
+	s.True(errors.Is(internalErrors[0], ErrInternal))
+	s.Contains(internalErrors[0].Error(), "request dump: panic: body skipper failure")
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errReadFailed
+}
+
+var errReadFailed = errors.New("read failed")
+
+func (s *MiddlewareTestSuite) TestInternalErrorOnBodyRead() {
+	var internalErrors []error
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IsBodyDump: true,
+		OnInternalError: func(err error, _ echo.Context) {
+			internalErrors = append(internalErrors, err)
+		},
+	}))
+
+	var handlerErr error
+	s.e.POST("/", func(c echo.Context) error {
+		_, handlerErr = io.ReadAll(c.Request().Body)
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", failingReader{}))
+
+	s.Len(internalErrors, 1)
+	s.True(errors.Is(internalErrors[0], ErrInternal))
+	s.True(errors.Is(internalErrors[0], errReadFailed))
+	s.True(errors.Is(handlerErr, errReadFailed))
+}
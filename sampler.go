@@ -0,0 +1,29 @@
+package echosentrymiddleware
+
+import (
+	"context"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// echoContextKey stores the echo.Context of the request in the context of its transaction for TracesSampler
+type echoContextKey struct{}
+
+func withEchoContext(ctx context.Context, c echo.Context) context.Context {
+	return context.WithValue(ctx, echoContextKey{}, c)
+}
+
+// EchoTracesSampler decides the sample rate of a transaction with access to the echo.Context of the request
+// (route, headers, values set by other middleware). c is nil for transactions not started by the middleware.
+type EchoTracesSampler func(c echo.Context, ctx sentry.SamplingContext) float64
+
+// NewTracesSampler returns a sentry.TracesSampler for ClientOptions passing the echo.Context of the request
+// to the sampler, so sampling logic lives in one place
+func NewTracesSampler(sampler EchoTracesSampler) sentry.TracesSampler {
+	return func(ctx sentry.SamplingContext) float64 {
+		c, _ := ctx.Span.Context().Value(echoContextKey{}).(echo.Context)
+
+		return sampler(c, ctx)
+	}
+}
@@ -0,0 +1,53 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestTracesSampler() {
+	var paths []string
+
+	err := sentry.Init(sentry.ClientOptions{
+		EnableTracing: true,
+		Transport:     s.transport,
+		TracesSampler: NewTracesSampler(func(c echo.Context, _ sentry.SamplingContext) float64 {
+			if c == nil {
+				paths = append(paths, "")
+				return 0
+			}
+
+			paths = append(paths, c.Path())
+
+			if c.Request().Header.Get("X-Tenant") == "vip" {
+				return 1
+			}
+
+			return 0
+		}),
+	})
+	s.Require().NoError(err)
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{}))
+
+	var span *sentry.Span
+	s.e.GET("/users/:id", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	s.Equal(sentry.SampledFalse, span.Sampled)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Tenant", "vip")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+	s.Equal(sentry.SampledTrue, span.Sampled)
+
+	sentry.StartTransaction(sentry.SetHubOnContext(req.Context(), sentry.CurrentHub()), "manual").Finish()
+
+	s.Equal([]string{"/users/:id", "/users/:id", ""}, paths)
+}
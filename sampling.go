@@ -0,0 +1,65 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// ParentSamplingPolicy defines how the parent's sampled flag is treated when continuing a trace
+type ParentSamplingPolicy int
+
+const (
+	// ParentSamplingInherit keeps the sampling decision of the upstream service
+	ParentSamplingInherit ParentSamplingPolicy = iota
+	// ParentSamplingForceOnError keeps the upstream decision, but sends failed requests of traces the upstream
+	// service did not sample
+	ParentSamplingForceOnError
+	// ParentSamplingIgnore ignores the upstream decision and samples with the local SDK settings
+	ParentSamplingIgnore
+)
+
+func parentSamplingOption(policy ParentSamplingPolicy) sentry.SpanOption {
+	return func(span *sentry.Span) {
+		if policy == ParentSamplingIgnore {
+			span.Sampled = sentry.SampledUndefined
+		}
+	}
+}
+
+// applyParentSampling sends the unsampled continued trace of a failed request with ParentSamplingForceOnError,
+// the dynamic sampling context is updated to match the new decision
+func applyParentSampling(policy ParentSamplingPolicy, span *sentry.Span, request *http.Request, status int, err error) {
+	if policy != ParentSamplingForceOnError || span.Sampled == sentry.SampledTrue || !isParentUnsampled(request) {
+		return
+	}
+
+	if err == nil && status < http.StatusInternalServerError {
+		return
+	}
+
+	// do not send anything if tracing is disabled in the SDK
+	if !isTracingEnabled(sentry.CurrentHub()) {
+		return
+	}
+
+	dsc, _ := sentry.DynamicSamplingContextFromHeader([]byte(span.ToBaggage()))
+	if dsc.Entries == nil {
+		dsc.Entries = map[string]string{"trace_id": span.TraceID.String()}
+	}
+
+	dsc.Entries["sampled"] = "true"
+	dsc.Entries["sample_rate"] = "1"
+	dsc.Frozen = true
+
+	span.SetDynamicSamplingContext(dsc)
+	span.Sampled = sentry.SampledTrue
+}
+
+// isParentUnsampled reports whether the request continues a trace the upstream service decided not to sample
+func isParentUnsampled(request *http.Request) bool {
+	parts := strings.Split(request.Header.Get(sentry.SentryTraceHeader), "-")
+
+	return len(parts) == 3 && parts[2] == "0"
+}
@@ -0,0 +1,106 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	sampledTrace   = "d49d9bf66f13450b81f65bc51cf49c03-1000000000000000-1"
+	unsampledTrace = "d49d9bf66f13450b81f65bc51cf49c03-1000000000000000-0"
+)
+
+func (s *MiddlewareTestSuite) TestParentSampling() {
+	tests := []struct {
+		name    string
+		policy  ParentSamplingPolicy
+		trace   string
+		baggage string
+		status  int
+		want    sentry.Sampled
+		wantDSC map[string]string
+	}{
+		{
+			name:   "inherit sampled",
+			policy: ParentSamplingInherit,
+			trace:  sampledTrace,
+			status: http.StatusOK,
+			want:   sentry.SampledTrue,
+		},
+		{
+			name:   "inherit unsampled on error",
+			policy: ParentSamplingInherit,
+			trace:  unsampledTrace,
+			status: http.StatusInternalServerError,
+			want:   sentry.SampledFalse,
+		},
+		{
+			name:    "force on error",
+			policy:  ParentSamplingForceOnError,
+			trace:   unsampledTrace,
+			baggage: "sentry-trace_id=d49d9bf66f13450b81f65bc51cf49c03,sentry-sample_rate=0.1,sentry-sampled=false",
+			status:  http.StatusInternalServerError,
+			want:    sentry.SampledTrue,
+			wantDSC: map[string]string{
+				"trace_id":    "d49d9bf66f13450b81f65bc51cf49c03",
+				"sample_rate": "1",
+				"sampled":     "true",
+			},
+		},
+		{
+			name:   "force on error without parent",
+			policy: ParentSamplingForceOnError,
+			status: http.StatusInternalServerError,
+			want:   sentry.SampledFalse, // TracesSampleRate is 0
+		},
+		{
+			name:   "force on error without error",
+			policy: ParentSamplingForceOnError,
+			trace:  unsampledTrace,
+			status: http.StatusOK,
+			want:   sentry.SampledFalse,
+		},
+		{
+			name:   "ignore",
+			policy: ParentSamplingIgnore,
+			trace:  sampledTrace,
+			status: http.StatusOK,
+			want:   sentry.SampledFalse, // TracesSampleRate is 0
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			e := echo.New()
+			e.Use(MiddlewareWithConfig(SentryConfig{ParentSampling: tt.policy}))
+
+			var span *sentry.Span
+			e.GET("/", func(c echo.Context) error {
+				span = sentry.TransactionFromContext(c.Request().Context())
+				return c.NoContent(tt.status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(sentry.SentryTraceHeader, tt.trace)
+			req.Header.Set(sentry.SentryBaggageHeader, tt.baggage)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			s.Require().NotNil(span)
+			s.Equal(tt.want, span.Sampled)
+
+			if tt.trace != "" {
+				s.Equal("d49d9bf66f13450b81f65bc51cf49c03", span.TraceID.String())
+			}
+
+			if tt.wantDSC != nil {
+				dsc, err := sentry.DynamicSamplingContextFromHeader([]byte(span.ToBaggage()))
+				s.Require().NoError(err)
+				s.Equal(tt.wantDSC, dsc.Entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package echosentrymiddleware
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxTagNameSize is the limit of tag names in Sentry
+	maxTagNameSize = 32
+	// maxTagValueSize is the limit of tag values in Sentry
+	maxTagValueSize = 200
+)
+
+// Sanitizer normalizes tag names and values before they are set,
+// e.g. to fit the limits of a self-hosted Sentry or relay rules
+type Sanitizer interface {
+	TagName(name string) string
+	TagValue(value string) string
+}
+
+// WhitespaceMode defines how DefaultSanitizer treats whitespace in tag values
+type WhitespaceMode int
+
+const (
+	// WhitespaceReplaceNewlines replaces newlines with spaces
+	WhitespaceReplaceNewlines WhitespaceMode = iota
+	// WhitespacePreserve keeps whitespace as is
+	WhitespacePreserve
+	// WhitespaceCollapse replaces runs of whitespace with a single space
+	WhitespaceCollapse
+	// WhitespaceEscape escapes newlines, carriage returns and tabs as \n, \r and \t, keeping dumped JSON readable
+	WhitespaceEscape
+)
+
+var whitespaceEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// DefaultSanitizer applies the limits of sentry.io: names are truncated to 32 bytes,
+// values to 200 bytes with dots. Invalid UTF-8 in values is hex-escaped.
+type DefaultSanitizer struct {
+	// Whitespace defines the whitespace handling of values, newlines are replaced with spaces by default
+	Whitespace WhitespaceMode
+}
+
+// TagName truncates the name to 32 bytes
+func (DefaultSanitizer) TagName(name string) string {
+	return limitString(name, maxTagNameSize)
+}
+
+// TagValue escapes invalid UTF-8, normalizes whitespace and truncates the value to 200 bytes
+func (s DefaultSanitizer) TagValue(value string) string {
+	return limitStringWithDots(normalizeWhitespace(escapeInvalidUTF8(value), s.Whitespace), maxTagValueSize)
+}
+
+// escapeInvalidUTF8 replaces bytes of invalid UTF-8 sequences with \xNN,
+// otherwise Sentry drops the whole value or event
+func escapeInvalidUTF8(value string) string {
+	if utf8.ValidString(value) {
+		return value
+	}
+
+	const hex = "0123456789abcdef"
+
+	var b strings.Builder
+
+	b.Grow(len(value) + 8)
+
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(`\x`)
+			b.WriteByte(hex[value[i]>>4])
+			b.WriteByte(hex[value[i]&0xf])
+		} else {
+			b.WriteString(value[i : i+size])
+		}
+
+		i += size
+	}
+
+	return b.String()
+}
+
+func normalizeWhitespace(value string, mode WhitespaceMode) string {
+	switch mode {
+	case WhitespacePreserve:
+		return value
+	case WhitespaceCollapse:
+		return strings.Join(strings.Fields(value), " ")
+	case WhitespaceEscape:
+		return whitespaceEscaper.Replace(value)
+	default:
+		return strings.ReplaceAll(value, "\n", " ") // no \n in strings
+	}
+}
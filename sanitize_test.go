@@ -0,0 +1,123 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultSanitizerTagValue(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		want string
+	}{
+		{
+			name: "Short string",
+			str:  "i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7",
+			want: "i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7i3WEG3605Kj7",
+		},
+		{
+			name: "Long string containing \\n",
+			str:  "05Kj7z2AXCl603gMJu6B23z2sD05\nKj7z2AXCl603gMJu6B23z2sD05Kj7z\n2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD",
+			want: "05Kj7z2AXCl603gMJu6B23z2sD05 Kj7z2AXCl603gMJu6B23z2sD05Kj7z 2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AXCl60...",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.True(t, len(DefaultSanitizer{}.TagValue(tt.str)) <= 200)
+			require.Equal(t, tt.want, DefaultSanitizer{}.TagValue(tt.str))
+		})
+	}
+}
+
+func TestDefaultSanitizerTagName(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		want string
+	}{
+		{
+			name: "Short string",
+			str:  "i3WEG3605Kj7",
+			want: "i3WEG3605Kj7",
+		},
+		{
+			name: "Long string",
+			str:  "05Kj7z2AXCl603gMJu6B23z2sD05Kj7z2AX",
+			want: "05Kj7z2AXCl603gMJu6B23z2sD05Kj7z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.True(t, len(DefaultSanitizer{}.TagName(tt.str)) <= 32)
+			require.Equal(t, tt.want, DefaultSanitizer{}.TagName(tt.str))
+		})
+	}
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	value := "{\n\t\"a\":  1\r\n}"
+
+	require.Equal(t, "{ \t\"a\":  1\r }", normalizeWhitespace(value, WhitespaceReplaceNewlines))
+	require.Equal(t, value, normalizeWhitespace(value, WhitespacePreserve))
+	require.Equal(t, "{ \"a\": 1 }", normalizeWhitespace(value, WhitespaceCollapse))
+	require.Equal(t, `{\n\t"a":  1\r\n}`, normalizeWhitespace(value, WhitespaceEscape))
+	require.Equal(t, `{\n\t"a":  1\r\n}`, DefaultSanitizer{Whitespace: WhitespaceEscape}.TagValue(value))
+}
+
+func TestEscapeInvalidUTF8(t *testing.T) {
+	require.Equal(t, "héllo", escapeInvalidUTF8("héllo"))
+	require.Equal(t, `a\xffb\xc3`, escapeInvalidUTF8("a\xffb\xc3"))
+	require.Equal(t, `\xff \xfe`, DefaultSanitizer{}.TagValue("\xff\n\xfe"))
+}
+
+func (s *MiddlewareTestSuite) TestInvalidUTF8() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		AreHeadersDump: true,
+		IsBodyDump:     true,
+	}))
+
+	var span *sentry.Span
+	s.e.POST("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body\xff"))
+	req.Header.Set("X-Custom", "value\xfe")
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+	s.Equal(`body\xff`, span.Tags["req.body"])
+	s.Equal(`value\xfe`, span.Tags["req.header.X-Custom"])
+}
+
+type upperSanitizer struct{}
+
+func (upperSanitizer) TagName(name string) string {
+	return strings.ToUpper(name)
+}
+
+func (upperSanitizer) TagValue(value string) string {
+	return strings.ToUpper(value)
+}
+
+func (s *MiddlewareTestSuite) TestSanitizer() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		Sanitizer: upperSanitizer{},
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/users/:id", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+	s.Equal("ABC", span.Tags["PATH.ID"])
+	s.Equal("/USERS/:ID", span.Tags["PATH"])
+}
@@ -0,0 +1,62 @@
+package echosentrymiddleware
+
+import (
+	"maps"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// isTracingEnabled reports whether the client of the hub records transactions at all
+func isTracingEnabled(hub *sentry.Hub) bool {
+	client := hub.Client()
+
+	return client != nil && client.Options().EnableTracing
+}
+
+// dumpRequestToScope copies the request metadata of the span to the request scoped hub before the handler.
+// It is used when tracing is disabled, so error events captured during the request are still enriched.
+func dumpRequestToScope(span *sentry.Span) {
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil || isTracingEnabled(hub) {
+		return
+	}
+
+	copyToScope(hub.Scope(), span)
+}
+
+// dumpResponseToScope adds the response metadata recorded after the handler and the request breadcrumb
+func dumpResponseToScope(c echo.Context, config SentryConfig, span *sentry.Span, request *http.Request, status int) {
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil || isTracingEnabled(hub) {
+		return
+	}
+
+	copyToScope(hub.Scope(), span)
+
+	// the URL is scrubbed or omitted the way the request_uri tag is
+	url := getRoute(c, config)
+	if !config.OmitRequestURI {
+		url = guardCardinality(config.CardinalityGuard, config.URLScrubber(request.RequestURI))
+	}
+
+	hub.AddBreadcrumb(&sentry.Breadcrumb{
+		Type:     "http",
+		Category: "http.server",
+		Data: map[string]interface{}{
+			"method":      request.Method,
+			"url":         url,
+			"status_code": status,
+		},
+		Level: sentry.LevelInfo,
+	}, nil)
+}
+
+func copyToScope(scope *sentry.Scope, span *sentry.Span) {
+	scope.SetTags(span.Tags)
+
+	if len(span.Data) > 0 {
+		scope.SetContext("request.data", maps.Clone(span.Data))
+	}
+}
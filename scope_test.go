@@ -0,0 +1,91 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestTracingDisabled() {
+	s.NoError(sentry.Init(sentry.ClientOptions{
+		Transport: s.transport,
+	}))
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors: true,
+	}))
+	s.e.GET("/users/:id", func(echo.Context) error {
+		return echo.NewHTTPError(http.StatusInternalServerError, "test error")
+	})
+
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	s.Equal(http.StatusInternalServerError, rec.Code)
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+	s.Equal("42", events[0].Tags["path.id"])
+	s.Equal("500", events[0].Tags[respStatus])
+	s.Contains(events[0].Contexts, "request.data")
+	s.Len(events[0].Breadcrumbs, 1)
+	s.Equal(http.StatusInternalServerError, events[0].Breadcrumbs[0].Data["status_code"])
+}
+
+func (s *MiddlewareTestSuite) TestTracingDisabledBreadcrumbURL() {
+	tests := []struct {
+		name           string
+		omitRequestURI bool
+		want           string
+	}{
+		{name: "scrubbed", want: "/users/42?token=" + scrubbedValue},
+		{name: "omitted", omitRequestURI: true, want: "/users/:id"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			s.NoError(sentry.Init(sentry.ClientOptions{
+				Transport: s.transport,
+			}))
+
+			s.e.Use(MiddlewareWithConfig(SentryConfig{
+				CaptureErrors:  true,
+				OmitRequestURI: tt.omitRequestURI,
+			}))
+			s.e.GET("/users/:id", func(echo.Context) error {
+				return echo.ErrInternalServerError
+			})
+
+			s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42?token=secret", nil))
+
+			events := s.transport.Events()
+			s.Require().Len(events, 1)
+			s.Require().Len(events[0].Breadcrumbs, 1)
+			s.Equal(tt.want, events[0].Breadcrumbs[0].Data["url"])
+		})
+	}
+}
+
+func (s *MiddlewareTestSuite) TestTracingDisabledHandlerEvents() {
+	s.NoError(sentry.Init(sentry.ClientOptions{
+		Transport: s.transport,
+	}))
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{}))
+	s.e.GET("/users/:id", func(c echo.Context) error {
+		CaptureMessage(c, "from handler")
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	events := s.transport.Events()
+	s.Require().Len(events, 1)
+	s.Equal("from handler", events[0].Message)
+	s.Equal("42", events[0].Tags["path.id"])
+	s.Contains(events[0].Contexts, "request.data")
+	s.NotContains(events[0].Tags, respStatus)
+}
@@ -0,0 +1,25 @@
+package echosentrymiddleware
+
+import (
+	"strings"
+)
+
+// scrubbedValue replaces values of sensitive fields
+const scrubbedValue = "[scrubbed]"
+
+// DefaultSensitiveFields is the default list of sensitive field names
+var DefaultSensitiveFields = []string{
+	"password", "passwd", "secret", "token", "api_key", "apikey", "authorization", "card_number", "cvv",
+}
+
+func isSensitive(name string, sensitiveFields []string) bool {
+	name = strings.ToLower(name)
+
+	for _, field := range sensitiveFields {
+		if strings.Contains(name, strings.ToLower(field)) {
+			return true
+		}
+	}
+
+	return false
+}
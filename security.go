@@ -0,0 +1,138 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// maxSecurityCounters limits memory used by security events on many clients
+const maxSecurityCounters = 10000
+
+// security event categories, tagged as "security.category"
+const (
+	securityAuthFailures   = "auth_failures"
+	securityOversized      = "oversized_request"
+	securityMalformedFlood = "malformed_requests"
+)
+
+var securityMessages = map[string]string{
+	securityAuthFailures:   "security: burst of authentication failures",
+	securityOversized:      "security: oversized request rejected",
+	securityMalformedFlood: "security: flood of malformed requests",
+}
+
+// SecurityEvents captures dedicated warning events for bursts of authentication failures (401, 403),
+// rejections of oversized requests (413, 414, 431) and floods of malformed requests (400) of a client IP.
+// An event is captured at most once per category and client within the window.
+type SecurityEvents struct {
+	window    time.Duration
+	threshold int
+
+	mu       sync.Mutex
+	counters map[securityKey]*securityCounter
+}
+
+type securityKey struct {
+	category string
+	client   string
+}
+
+type securityCounter struct {
+	start    time.Time
+	count    int
+	reported bool
+}
+
+// NewSecurityEvents returns security events capturing bursts and floods of threshold responses of a client
+// within the window, oversized requests are captured from the first one
+func NewSecurityEvents(window time.Duration, threshold int) *SecurityEvents {
+	return &SecurityEvents{
+		window:    window,
+		threshold: max(threshold, 1),
+		counters:  make(map[securityKey]*securityCounter),
+	}
+}
+
+// getSecurityCategory returns the category of the response status and the number of responses triggering an event
+func (s *SecurityEvents) getSecurityCategory(status int) (string, int) {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return securityAuthFailures, s.threshold
+	case http.StatusRequestEntityTooLarge, http.StatusRequestURITooLong, http.StatusRequestHeaderFieldsTooLarge:
+		return securityOversized, 1
+	case http.StatusBadRequest:
+		return securityMalformedFlood, s.threshold
+	}
+
+	return "", 0
+}
+
+// observe counts the response and reports whether an event is captured, with the count in the window
+func (s *SecurityEvents) observe(key securityKey, threshold int, now time.Time) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || now.Sub(counter.start) >= s.window {
+		if !ok && len(s.counters) >= maxSecurityCounters {
+			clear(s.counters)
+		}
+
+		counter = &securityCounter{start: now}
+		s.counters[key] = counter
+	}
+
+	counter.count++
+
+	if counter.reported || counter.count < threshold {
+		return 0, false
+	}
+
+	counter.reported = true
+
+	return counter.count, true
+}
+
+// captureSecurityEvent captures a security event when the response completes a burst or a flood of the client
+func captureSecurityEvent(c echo.Context, config SentryConfig, span *sentry.Span, status int) {
+	if config.SecurityEvents == nil {
+		return
+	}
+
+	category, threshold := config.SecurityEvents.getSecurityCategory(status)
+	if category == "" {
+		return
+	}
+
+	client, _ := getClientIP(c, config)
+
+	count, ok := config.SecurityEvents.observe(securityKey{category: category, client: client}, threshold, config.Clock())
+	if !ok {
+		return
+	}
+
+	setTag(span, config, "security.event", category)
+
+	hub := sentry.GetHubFromContext(span.Context())
+	if hub == nil {
+		return
+	}
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetLevel(sentry.LevelWarning)
+		scope.SetFingerprint([]string{"echosentrymiddleware.security", category})
+		scope.SetTag("security.category", category)
+		scope.SetTag("path", c.Path())
+		scope.SetTag("client_ip", config.Sanitizer.TagValue(client))
+		scope.SetTag("resp.status", strconv.Itoa(status))
+		scope.SetExtra("security.count", count)
+		scope.SetExtra("security.window", config.SecurityEvents.window.String())
+
+		hub.CaptureMessage(securityMessages[category])
+	})
+}
@@ -0,0 +1,94 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityEventsObserve(t *testing.T) {
+	events := NewSecurityEvents(time.Minute, 3)
+	key := securityKey{category: securityAuthFailures, client: "1.2.3.4"}
+	now := time.Now()
+
+	var captured []int
+
+	for i := range 5 {
+		if count, ok := events.observe(key, 3, now.Add(time.Duration(i)*time.Second)); ok {
+			captured = append(captured, count)
+		}
+	}
+
+	require.Equal(t, []int{3}, captured)
+
+	// a new window starts counting again
+	_, ok := events.observe(key, 3, now.Add(2*time.Minute))
+	require.False(t, ok)
+
+	// other clients are counted separately
+	count, ok := events.observe(securityKey{category: securityOversized, client: "5.6.7.8"}, 1, now)
+	require.True(t, ok)
+	require.Equal(t, 1, count)
+}
+
+func (s *MiddlewareTestSuite) TestSecurityEvents() {
+	now := time.Now()
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		SecurityEvents: NewSecurityEvents(time.Minute, 3),
+		Clock: func() time.Time {
+			return now
+		},
+	}))
+	s.e.POST("/login", func(c echo.Context) error {
+		return c.NoContent(http.StatusUnauthorized)
+	})
+	s.e.POST("/upload", func(c echo.Context) error {
+		return c.NoContent(http.StatusRequestEntityTooLarge)
+	})
+	s.e.POST("/ok", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	send := func(target string) {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req.RemoteAddr = "1.2.3.4:1234"
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	for range 5 {
+		send("/login")
+		send("/ok")
+	}
+
+	send("/upload")
+	send("/upload")
+
+	events := s.errorEvents()
+	s.Require().Len(events, 2)
+
+	s.Equal(sentry.LevelWarning, events[0].Level)
+	s.Equal("security: burst of authentication failures", events[0].Message)
+	s.Equal(securityAuthFailures, events[0].Tags["security.category"])
+	s.Equal("1.2.3.4", events[0].Tags["client_ip"])
+	s.Equal("/login", events[0].Tags["path"])
+	s.Equal(3, events[0].Extra["security.count"])
+	s.Equal([]string{"echosentrymiddleware.security", securityAuthFailures}, events[0].Fingerprint)
+
+	s.Equal(securityOversized, events[1].Tags["security.category"])
+	s.Equal("413", events[1].Tags["resp.status"])
+
+	// the window is over, the next burst is captured again
+	now = now.Add(time.Minute)
+
+	for range 3 {
+		send("/login")
+	}
+
+	s.Len(s.errorEvents(), 3)
+}
@@ -0,0 +1,137 @@
+// Package sentrytest provides a harness to assert on transactions emitted by the echo Sentry middleware
+package sentrytest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	echosentrymiddleware "github.com/adlandh/echo-sentry-middleware"
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+var _ sentry.Transport = (*Transport)(nil)
+
+// Transport records events instead of sending them
+type Transport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+// Configure is a no-op
+func (*Transport) Configure(sentry.ClientOptions) {}
+
+// SendEvent records the event
+func (t *Transport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events = append(t.events, event)
+}
+
+// Flush is a no-op, events are recorded synchronously
+func (*Transport) Flush(time.Duration) bool {
+	return true
+}
+
+// Close is a no-op
+func (*Transport) Close() {}
+
+// Events returns all recorded events
+func (t *Transport) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*sentry.Event(nil), t.events...)
+}
+
+// Reset drops recorded events
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events = nil
+}
+
+// Transaction is a transaction event emitted by the middleware
+type Transaction struct {
+	*sentry.Event
+}
+
+// Tag returns the tag of the transaction
+func (t *Transaction) Tag(name string) string {
+	return t.Tags[name]
+}
+
+// Data returns the data of the transaction
+func (t *Transaction) Data(key string) interface{} {
+	return t.Extra[key]
+}
+
+// Span returns the first child span with the op, or nil
+func (t *Transaction) Span(op string) *sentry.Span {
+	for _, span := range t.Spans {
+		if span.Op == op {
+			return span
+		}
+	}
+
+	return nil
+}
+
+// Harness wires an echo instance with the middleware and a recording transport.
+// It initializes the global Sentry client, so harnesses must not be used in parallel tests.
+type Harness struct {
+	Echo      *echo.Echo
+	Transport *Transport
+}
+
+// New initializes Sentry with tracing enabled and all transactions sampled
+// and returns a harness with the middleware configured with config
+func New(config echosentrymiddleware.SentryConfig) (*Harness, error) {
+	transport := &Transport{}
+
+	if err := sentry.Init(sentry.ClientOptions{
+		EnableTracing:    true,
+		TracesSampleRate: 1,
+		Transport:        transport,
+	}); err != nil {
+		return nil, err
+	}
+
+	e := echo.New()
+	e.Use(echosentrymiddleware.MiddlewareWithConfig(config))
+
+	return &Harness{Echo: e, Transport: transport}, nil
+}
+
+// DoRequest serves the request and returns the transaction emitted for it (nil if none was sent) and the response
+func (h *Harness) DoRequest(req *http.Request) (*Transaction, *http.Response) {
+	before := len(h.Transport.Events())
+
+	rec := httptest.NewRecorder()
+	h.Echo.ServeHTTP(rec, req)
+
+	for _, event := range h.Transport.Events()[before:] {
+		if event.Type == "transaction" {
+			return &Transaction{Event: event}, rec.Result()
+		}
+	}
+
+	return nil, rec.Result()
+}
+
+// ErrorEvents returns the recorded events which are not transactions
+func (h *Harness) ErrorEvents() []*sentry.Event {
+	var events []*sentry.Event
+
+	for _, event := range h.Transport.Events() {
+		if event.Type != "transaction" {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
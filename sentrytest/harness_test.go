@@ -0,0 +1,43 @@
+package sentrytest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echosentrymiddleware "github.com/adlandh/echo-sentry-middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarness(t *testing.T) {
+	h, err := New(echosentrymiddleware.SentryConfig{
+		CaptureErrors: true,
+	})
+	require.NoError(t, err)
+
+	h.Echo.GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "user")
+	})
+	h.Echo.GET("/error", func(echo.Context) error {
+		return errors.New("test error")
+	})
+
+	tx, resp := h.DoRequest(httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotNil(t, tx)
+	require.Equal(t, "42", tx.Tag("path.id"))
+	require.Equal(t, "200", tx.Tag("resp.status"))
+	require.NotNil(t, tx.Data("req.headers.count"))
+	require.NotNil(t, tx.Span("http.handler"))
+	require.Nil(t, tx.Span("unknown"))
+
+	tx, resp = h.DoRequest(httptest.NewRequest(http.MethodGet, "/error", nil))
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.NotNil(t, tx)
+	require.Len(t, h.ErrorEvents(), 1)
+
+	h.Transport.Reset()
+	require.Empty(t, h.Transport.Events())
+}
@@ -0,0 +1,37 @@
+package echosentrymiddleware
+
+import (
+	"os"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// ServerIdentity describes the instance handling the requests
+type ServerIdentity struct {
+	Hostname         string
+	InstanceID       string
+	AvailabilityZone string
+}
+
+// ServerIdentityProvider returns the identity of the server, it is called once on middleware creation
+type ServerIdentityProvider func() ServerIdentity
+
+// EnvServerIdentity takes the hostname from the OS and the instance ID and availability zone
+// from INSTANCE_ID and AVAILABILITY_ZONE environment variables
+func EnvServerIdentity() ServerIdentity {
+	hostname, _ := os.Hostname()
+
+	return ServerIdentity{
+		Hostname:         hostname,
+		InstanceID:       os.Getenv("INSTANCE_ID"),
+		AvailabilityZone: os.Getenv("AVAILABILITY_ZONE"),
+	}
+}
+
+func dumpServerIdentity(span *sentry.Span, config SentryConfig) {
+	identity := config.serverIdentity
+
+	setTag(span, config, "server.hostname", identity.Hostname)
+	setTag(span, config, "server.instance_id", identity.InstanceID)
+	setTag(span, config, "server.availability_zone", identity.AvailabilityZone)
+}
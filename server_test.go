@@ -0,0 +1,34 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestServerIdentity() {
+	s.T().Setenv("INSTANCE_ID", "i-123")
+	s.T().Setenv("AVAILABILITY_ZONE", "eu-west-1a")
+
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		ServerIdentity: EnvServerIdentity,
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	hostname, _ := os.Hostname()
+
+	s.NotNil(span)
+	s.Equal(hostname, span.Tags["server.hostname"])
+	s.Equal("i-123", span.Tags["server.instance_id"])
+	s.Equal("eu-west-1a", span.Tags["server.availability_zone"])
+}
@@ -0,0 +1,80 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"slices"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// maxSOAPPeekSize limits the bytes of the request body read to find the SOAP operation
+const maxSOAPPeekSize = 16 << 10
+
+// isSOAPRequest reports whether the request is a SOAP 1.1 (with SOAPAction header, even empty) or SOAP 1.2 call
+func isSOAPRequest(request *http.Request) bool {
+	if request.Method != http.MethodPost {
+		return false
+	}
+
+	if len(request.Header.Values(headerSOAPAction)) > 0 {
+		return true
+	}
+
+	return getMediaType(request.Header) == "application/soap+xml"
+}
+
+// getSOAPOperation returns the name of the first element in the Body of the SOAP envelope
+func getSOAPOperation(body []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	// path of the operation element: Envelope, Body, operation
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case depth == 0 && t.Name.Local == "Envelope", depth == 1 && t.Name.Local == "Body":
+				depth++
+			case depth == 2:
+				return t.Name.Local
+			default:
+				// headers and other elements outside of the Body are skipped
+				if err := decoder.Skip(); err != nil {
+					return ""
+				}
+			}
+		case xml.EndElement:
+			return ""
+		}
+	}
+}
+
+// getSOAPName returns the SOAP action, or the operation of the body when the action is empty,
+// to name transactions of SOAP calls posted to one of SOAPRoutes
+func getSOAPName(c echo.Context, config SentryConfig, request *http.Request) (action, operation string) {
+	if !slices.Contains(config.SOAPRoutes, c.Path()) || !isSOAPRequest(request) ||
+		request.Body == nil || request.Body == http.NoBody {
+		return "", ""
+	}
+
+	if action = getXMLAction(request.Header); action != "" {
+		return guardCardinality(config.CardinalityGuard, action), ""
+	}
+
+	return "", guardCardinality(config.CardinalityGuard, getSOAPOperation(peekReqBody(request, maxSOAPPeekSize)))
+}
+
+// dumpSOAP tags the SOAP action or operation of the request
+func dumpSOAP(span *sentry.Span, config SentryConfig, action, operation string) {
+	setTag(span, config, "soap.action", action)
+	setTag(span, config, "soap.operation", operation)
+}
@@ -0,0 +1,119 @@
+package echosentrymiddleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const soapEnvelope = `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:u="http://example.com/users">
+  <soap:Header><u:Auth><u:Token>secret</u:Token></u:Auth></soap:Header>
+  <soap:Body><u:GetUser><u:ID>42</u:ID></u:GetUser></soap:Body>
+</soap:Envelope>`
+
+func TestGetSOAPOperation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "envelope", body: soapEnvelope, want: "GetUser"},
+		{name: "cut", body: soapEnvelope[:strings.Index(soapEnvelope, "<u:ID>")], want: "GetUser"},
+		{name: "empty body", body: `<Envelope><Body></Body></Envelope>`},
+		{name: "not soap", body: `<user><id>42</id></user>`},
+		{name: "not xml", body: `{"id":42}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, getSOAPOperation([]byte(tt.body)))
+		})
+	}
+}
+
+func (s *MiddlewareTestSuite) TestSOAP() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{SOAPRoutes: []string{"/soap"}}))
+
+	var received string
+	handler := func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		received = string(body)
+
+		return err
+	}
+	s.e.POST("/soap", handler)
+	s.e.POST("/xml", handler)
+
+	tests := []struct {
+		name        string
+		path        string
+		header      map[string]string
+		transaction string
+		tags        map[string]string
+	}{
+		{
+			name:        "SOAPAction header",
+			path:        "/soap",
+			header:      map[string]string{echo.HeaderContentType: "text/xml", headerSOAPAction: `"http://example.com/users/GetUser"`},
+			transaction: "http://example.com/users/GetUser",
+			tags:        map[string]string{"soap.action": "http://example.com/users/GetUser"},
+		},
+		{
+			name:        "SOAP 1.2 action",
+			path:        "/soap",
+			header:      map[string]string{echo.HeaderContentType: `application/soap+xml; action="GetUserAction"`},
+			transaction: "GetUserAction",
+			tags:        map[string]string{"soap.action": "GetUserAction"},
+		},
+		{
+			name:        "empty SOAPAction",
+			path:        "/soap",
+			header:      map[string]string{echo.HeaderContentType: "text/xml", headerSOAPAction: `""`},
+			transaction: "GetUser",
+			tags:        map[string]string{"soap.operation": "GetUser"},
+		},
+		{
+			name:        "plain xml",
+			path:        "/soap",
+			header:      map[string]string{echo.HeaderContentType: "text/xml"},
+			transaction: "HTTP POST /soap",
+		},
+		{
+			name:        "not a SOAP route",
+			path:        "/xml",
+			header:      map[string]string{echo.HeaderContentType: "text/xml", headerSOAPAction: `"http://example.com/users/GetUser"`},
+			transaction: "HTTP POST /xml",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(soapEnvelope))
+			req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+
+			s.e.ServeHTTP(httptest.NewRecorder(), req)
+			s.Equal(soapEnvelope, received)
+
+			events := s.transport.Events()
+			s.Require().NotEmpty(events)
+			event := events[len(events)-1]
+
+			s.Equal(tt.transaction, event.Transaction)
+
+			for name, value := range tt.tags {
+				s.Equal(value, event.Tags[name])
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package echosentrymiddleware
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// staticRoute is the kind of routes serving assets registered by echo's Static and File methods
+type staticRoute int
+
+const (
+	notStatic staticRoute = iota
+	// staticDirectory is a route of Static or StaticFS serving files under a prefix
+	staticDirectory
+	// staticFile is a route of File or FileFS serving a single file
+	staticFile
+)
+
+// staticRouteNames are the names echo gives to handlers of Static and File routes
+type staticRouteNames struct {
+	directory, file, fsFile string
+}
+
+// closureSuffixRe matches suffixes of closure names, which depend on where the closure was inlined
+// (e.g. common.file.func1 or common.file.1)
+var closureSuffixRe = regexp.MustCompile(`(\.(func)?\d+)+$`)
+
+// getStaticRouteNames observes the names on a throwaway instance instead of hardcoding echo internals
+var getStaticRouteNames = sync.OnceValue(func() staticRouteNames {
+	e := echo.New()
+	// nothing is read from the filesystem until the routes serve requests
+	filesystem := os.DirFS(os.TempDir())
+
+	return staticRouteNames{
+		directory: closureSuffixRe.ReplaceAllString(e.StaticFS("/", filesystem).Name, ""),
+		file:      closureSuffixRe.ReplaceAllString(e.File("/file", "file").Name, ""),
+		fsFile:    closureSuffixRe.ReplaceAllString(e.FileFS("/fs-file", "file", filesystem).Name, ""),
+	}
+})
+
+// getStaticRoute tells static routes by the handler names of echo's Static and File registrations,
+// directory routes also end with the * param
+func getStaticRoute(route echo.Route) staticRoute {
+	names := getStaticRouteNames()
+
+	switch closureSuffixRe.ReplaceAllString(route.Name, "") {
+	case names.directory:
+		if strings.HasSuffix(route.Path, "*") {
+			return staticDirectory
+		}
+	case names.file, names.fsFile:
+		return staticFile
+	}
+
+	return notStatic
+}
+
+// staticRoutes caches kinds of echo routes by method and path. Only registered routes are cached,
+// paths of unrouted requests (e.g. the guarded paths of HTTPMiddleware) would grow the cache without bound.
+type staticRoutes struct {
+	kinds sync.Map
+}
+
+func (r *staticRoutes) get(c echo.Context) staticRoute {
+	if r == nil || c.Echo() == nil || c.Path() == "" {
+		return notStatic
+	}
+
+	key := routeKey{method: c.Request().Method, route: c.Path()}
+	if kind, ok := r.kinds.Load(key); ok {
+		return kind.(staticRoute)
+	}
+
+	for _, route := range c.Echo().Routes() {
+		if route.Method == key.method && route.Path == key.route {
+			kind := getStaticRoute(*route)
+			r.kinds.Store(key, kind)
+
+			return kind
+		}
+	}
+
+	return notStatic
+}
+
+// dumpStaticRoute tags the kind of the static route and, for directories, the file resolved from the path
+// of the request. The file of File routes is not known outside of their handlers.
+func dumpStaticRoute(c echo.Context, config SentryConfig, span *sentry.Span, kind staticRoute) {
+	switch kind {
+	case staticDirectory:
+		setTag(span, config, "static", "directory")
+		// resolved the way echo's StaticDirectoryHandler does
+		setTag(span, config, "static.file", path.Clean(strings.TrimPrefix(c.Param("*"), "/")))
+	case staticFile:
+		setTag(span, config, "static", "file")
+	case notStatic:
+	}
+}
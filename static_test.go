@@ -0,0 +1,132 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetStaticRoute fails when echo changes how Static and File routes are named
+func TestGetStaticRoute(t *testing.T) {
+	names := getStaticRouteNames()
+	require.NotEqual(t, names.directory, names.file)
+	require.NotEqual(t, names.directory, names.fsFile)
+
+	e := echo.New()
+	filesystem := os.DirFS(t.TempDir())
+	handler := func(echo.Context) error { return nil }
+
+	tests := []struct {
+		name  string
+		route *echo.Route
+		want  staticRoute
+	}{
+		{name: "static", route: e.Static("/static", "."), want: staticDirectory},
+		{name: "static fs", route: e.StaticFS("/assets", filesystem), want: staticDirectory},
+		{name: "file", route: e.File("/favicon.ico", "favicon.ico"), want: staticFile},
+		{name: "file fs", route: e.FileFS("/index.html", "index.html", filesystem), want: staticFile},
+		{name: "directory handler without wildcard", route: e.GET("/dir", echo.StaticDirectoryHandler(filesystem, false)), want: notStatic},
+		{name: "handler", route: e.GET("/users/*", handler), want: notStatic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, getStaticRoute(*tt.route))
+		})
+	}
+
+	group := e.Group("/group")
+	group.Static("/static", ".")
+	group.File("/file", "file")
+
+	kinds := make(map[string]staticRoute)
+	for _, route := range e.Routes() {
+		kinds[route.Path] = getStaticRoute(*route)
+	}
+
+	require.Equal(t, staticDirectory, kinds["/group/static*"])
+	require.Equal(t, staticFile, kinds["/group/file"])
+}
+
+func TestStaticRoutesCache(t *testing.T) {
+	e := echo.New()
+	e.File("/favicon.ico", "favicon.ico")
+
+	routes := &staticRoutes{}
+
+	for _, path := range []string{"/favicon.ico", "/users/1", "/users/2"} {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, path, nil), httptest.NewRecorder())
+		c.SetPath(path)
+		routes.get(c)
+	}
+
+	var cached []routeKey
+
+	routes.kinds.Range(func(key, _ any) bool {
+		cached = append(cached, key.(routeKey))
+		return true
+	})
+
+	require.Equal(t, []routeKey{{method: http.MethodGet, route: "/favicon.ico"}}, cached)
+}
+
+func (s *MiddlewareTestSuite) TestStaticRoutes() {
+	dir := s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(dir, "js"), 0o700))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "js", "app.js"), []byte("console.log(1)"), 0o600))
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+	s.e.StaticFS("/assets/", os.DirFS(dir))
+	s.e.FileFS("/app.js", "js/app.js", os.DirFS(dir))
+
+	tests := []struct {
+		target      string
+		status      int
+		transaction string
+		tags        map[string]string
+	}{
+		{
+			target: "/assets/js/app.js", status: http.StatusOK, transaction: "HTTP GET /assets/*",
+			tags: map[string]string{"static": "directory", "static.file": "js/app.js"},
+		},
+		{
+			target: "/assets/missing.js", status: http.StatusNotFound, transaction: "HTTP GET /assets/*",
+			tags: map[string]string{"static": "directory", "static.file": "missing.js", "resp.body": "[excluded]"},
+		},
+		{
+			target: "/app.js", status: http.StatusOK, transaction: "HTTP GET /app.js",
+			tags: map[string]string{"static": "file"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.target, func() {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+			rec := httptest.NewRecorder()
+			s.e.ServeHTTP(rec, req)
+			s.Equal(tt.status, rec.Code)
+
+			events := s.transport.Events()
+			s.Require().NotEmpty(events)
+			event := events[len(events)-1]
+
+			s.Equal(tt.transaction, event.Transaction)
+			s.Equal(sentry.SourceRoute, event.TransactionInfo.Source)
+
+			for name, value := range tt.tags {
+				s.Equal(value, event.Tags[name], name)
+			}
+
+			if tt.tags["resp.body"] == "" {
+				s.NotContains(event.Tags, "resp.body")
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package echosentrymiddleware
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the counters shared by all instances of the middleware
+type Stats struct {
+	// RequestsSeen is the number of requests passed to the middleware
+	RequestsSeen int64 `json:"requests_seen"`
+	// RequestsSkipped is the number of requests skipped by Skipper
+	RequestsSkipped int64 `json:"requests_skipped"`
+	// TransactionsSampled is the number of finished transactions sent to Sentry
+	TransactionsSampled int64 `json:"transactions_sampled"`
+	// TransactionsOverQuota is the number of sampled transactions dropped by TransactionQuota
+	TransactionsOverQuota int64 `json:"transactions_over_quota"`
+	// ErrorsCaptured is the number of error events captured by the middleware
+	ErrorsCaptured int64 `json:"errors_captured"`
+	// Truncations is the number of response bodies truncated to MaxRespDumpSize
+	Truncations int64 `json:"truncations"`
+}
+
+var stats struct {
+	requestsSeen          atomic.Int64
+	requestsSkipped       atomic.Int64
+	transactionsSampled   atomic.Int64
+	transactionsOverQuota atomic.Int64
+	errorsCaptured        atomic.Int64
+	truncations           atomic.Int64
+}
+
+// CurrentStats returns the current values of the middleware counters
+func CurrentStats() Stats {
+	return Stats{
+		RequestsSeen:          stats.requestsSeen.Load(),
+		RequestsSkipped:       stats.requestsSkipped.Load(),
+		TransactionsSampled:   stats.transactionsSampled.Load(),
+		TransactionsOverQuota: stats.transactionsOverQuota.Load(),
+		ErrorsCaptured:        stats.errorsCaptured.Load(),
+		Truncations:           stats.truncations.Load(),
+	}
+}
+
+// PublishStats publishes the counters as the expvar variable with the name, e.g. for /debug/vars.
+// Like expvar.Publish it panics if the name is already registered.
+func PublishStats(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return CurrentStats() }))
+}
@@ -0,0 +1,54 @@
+package echosentrymiddleware
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestStats() {
+	before := CurrentStats()
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/health"
+		},
+		IsBodyDump:      true,
+		MaxRespDumpSize: 2,
+		CaptureErrors:   true,
+	}))
+	s.e.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	s.e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "response")
+	})
+	s.e.GET("/error", func(echo.Context) error {
+		return errors.New("test error")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("sentry-trace", sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+
+	after := CurrentStats()
+	s.Equal(int64(3), after.RequestsSeen-before.RequestsSeen)
+	s.Equal(int64(1), after.RequestsSkipped-before.RequestsSkipped)
+	s.Equal(int64(1), after.TransactionsSampled-before.TransactionsSampled)
+	s.Equal(int64(1), after.ErrorsCaptured-before.ErrorsCaptured)
+	s.Equal(int64(2), after.Truncations-before.Truncations) // both response bodies are longer than the limit
+
+	s.Run("expvar", func() {
+		PublishStats("echo_sentry_middleware_test")
+
+		var published Stats
+		s.NoError(json.Unmarshal([]byte(expvar.Get("echo_sentry_middleware_test").String()), &published))
+		s.Equal(after, published)
+	})
+}
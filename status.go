@@ -0,0 +1,50 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// errorSpanStatuses maps well-known errors to span statuses, more precise than the ones derived from their codes
+var errorSpanStatuses = []struct {
+	err    error
+	status sentry.SpanStatus
+}{
+	{echo.ErrUnauthorized, sentry.SpanStatusUnauthenticated},
+	{echo.ErrProxyAuthRequired, sentry.SpanStatusUnauthenticated},
+	{echo.ErrForbidden, sentry.SpanStatusPermissionDenied},
+	{middleware.ErrCSRFInvalid, sentry.SpanStatusPermissionDenied},
+	{middleware.ErrExtractorError, sentry.SpanStatusPermissionDenied},
+	{echo.ErrNotFound, sentry.SpanStatusNotFound},
+	{echo.ErrGone, sentry.SpanStatusNotFound},
+	{echo.ErrMethodNotAllowed, sentry.SpanStatusUnimplemented},
+	{echo.ErrRequestTimeout, sentry.SpanStatusDeadlineExceeded},
+	{echo.ErrConflict, sentry.SpanStatusAlreadyExists},
+	{echo.ErrPreconditionFailed, sentry.SpanStatusFailedPrecondition},
+	{echo.ErrPreconditionRequired, sentry.SpanStatusFailedPrecondition},
+	{echo.ErrStatusRequestEntityTooLarge, sentry.SpanStatusResourceExhausted},
+	{echo.ErrRequestHeaderFieldsTooLarge, sentry.SpanStatusResourceExhausted},
+	{echo.ErrRequestedRangeNotSatisfiable, sentry.SpanStatusOutOfRange},
+	{echo.ErrTooManyRequests, sentry.SpanStatusResourceExhausted},
+	{middleware.ErrRateLimitExceeded, sentry.SpanStatusResourceExhausted},
+	{echo.ErrNotImplemented, sentry.SpanStatusUnimplemented},
+	{echo.ErrServiceUnavailable, sentry.SpanStatusUnavailable},
+	{echo.ErrGatewayTimeout, sentry.SpanStatusDeadlineExceeded},
+	{context.DeadlineExceeded, sentry.SpanStatusDeadlineExceeded},
+	{context.Canceled, sentry.SpanStatusCanceled},
+}
+
+// getErrorSpanStatus returns the span status of the error, falling back to the one of the status code
+func getErrorSpanStatus(err error, status int) sentry.SpanStatus {
+	for _, s := range errorSpanStatuses {
+		if errors.Is(err, s.err) {
+			return s.status
+		}
+	}
+
+	return sentry.HTTPtoSpanStatus(status)
+}
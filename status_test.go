@@ -0,0 +1,37 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetErrorSpanStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		want   sentry.SpanStatus
+	}{
+		{"payload too large", echo.ErrStatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, sentry.SpanStatusResourceExhausted},
+		{"rate limit exceeded", middleware.ErrRateLimitExceeded, http.StatusTooManyRequests, sentry.SpanStatusResourceExhausted},
+		{"unauthorized", echo.ErrUnauthorized, http.StatusUnauthorized, sentry.SpanStatusUnauthenticated},
+		{"wrapped", fmt.Errorf("wrapped: %w", echo.ErrRequestTimeout), http.StatusRequestTimeout, sentry.SpanStatusDeadlineExceeded},
+		{"deadline exceeded", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusInternalServerError, sentry.SpanStatusDeadlineExceeded},
+		{"unknown http error", echo.NewHTTPError(http.StatusTeapot), http.StatusTeapot, sentry.SpanStatusInvalidArgument},
+		{"generic error", errors.New("test"), http.StatusInternalServerError, sentry.SpanStatusInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, getErrorSpanStatus(tt.err, tt.status))
+		})
+	}
+}
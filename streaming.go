@@ -0,0 +1,82 @@
+package echosentrymiddleware
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+const headerAcceptRanges = "Accept-Ranges"
+
+// streamedKind is the kind of responses copied from a file or a reader, "file" or "stream", empty for other responses
+type streamedKind struct {
+	kind string
+}
+
+// isFileResponse reports whether the response is served by c.File, c.Attachment or c.Inline (http.ServeContent)
+func isFileResponse(header http.Header) bool {
+	return header.Get(headerAcceptRanges) == "bytes" || header.Get(echo.HeaderContentDisposition) != ""
+}
+
+// isStreamMediaType reports whether the response is a stream of binary data or events, e.g. written by c.Stream
+func isStreamMediaType(mediaType string) bool {
+	switch mediaType {
+	case echo.MIMEOctetStream, "text/event-stream", "application/x-ndjson":
+		return true
+	}
+
+	return strings.HasPrefix(mediaType, "video/") || strings.HasPrefix(mediaType, "audio/")
+}
+
+// getFileName returns the file name of the Content-Disposition header, or the last element of the request path
+func getFileName(request *http.Request, header http.Header) string {
+	if _, params, err := mime.ParseMediaType(header.Get(echo.HeaderContentDisposition)); err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+
+	return path.Base(request.URL.Path)
+}
+
+// trackStreamedResponse records the file name or the stream content type of file and stream responses
+// and stops dumping their body, so big files are not buffered again
+func trackStreamedResponse(c echo.Context, config SentryConfig, span *sentry.Span, respDumper *bodyDumper) *streamedKind {
+	streamed := &streamedKind{}
+
+	resp := c.Response()
+	resp.Before(func() {
+		header := resp.Header()
+
+		switch mediaType := getMediaType(header); {
+		case isFileResponse(header):
+			streamed.kind = "file"
+			setTag(span, config, "resp.file", getFileName(c.Request(), header))
+		case isStreamMediaType(mediaType):
+			streamed.kind = "stream"
+			setTag(span, config, "resp.stream.content_type", mediaType)
+		default:
+			return
+		}
+
+		if respDumper != nil {
+			respDumper.SkipBody()
+		}
+	})
+
+	return streamed
+}
+
+// dumpStreamed records the kind and the bytes sent of file and stream responses
+func dumpStreamed(c echo.Context, config SentryConfig, span *sentry.Span, streamed *streamedKind) bool {
+	if streamed == nil || streamed.kind == "" {
+		return false
+	}
+
+	setTag(span, config, "resp.streamed", streamed.kind)
+	span.SetData("resp.bytes_sent", c.Response().Size)
+
+	return true
+}
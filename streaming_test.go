@@ -0,0 +1,76 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestStreamedResponses() {
+	file := filepath.Join(s.T().TempDir(), "report.csv")
+	s.Require().NoError(os.WriteFile(file, []byte(strings.Repeat("a,b\n", 100)), 0o600))
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{IsBodyDump: true}))
+	s.e.GET("/file", func(c echo.Context) error {
+		return c.File(file)
+	})
+	s.e.GET("/attachment", func(c echo.Context) error {
+		return c.Attachment(file, "export.csv")
+	})
+	s.e.GET("/stream", func(c echo.Context) error {
+		return c.Stream(http.StatusOK, echo.MIMEOctetStream, strings.NewReader("binary"))
+	})
+	s.e.GET("/json", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"key": "value"})
+	})
+
+	tests := []struct {
+		path     string
+		streamed string
+		tags     map[string]string
+		sent     int64
+	}{
+		{path: "/file", streamed: "file", tags: map[string]string{"resp.file": "file"}, sent: 400},
+		{path: "/attachment", streamed: "file", tags: map[string]string{"resp.file": "export.csv"}, sent: 400},
+		{
+			path: "/stream", streamed: "stream",
+			tags: map[string]string{"resp.stream.content_type": echo.MIMEOctetStream}, sent: 6,
+		},
+		{path: "/json"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.path, func() {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+			rec := httptest.NewRecorder()
+			s.e.ServeHTTP(rec, req)
+			s.Equal(http.StatusOK, rec.Code)
+
+			events := s.transport.Events()
+			s.Require().NotEmpty(events)
+			events = events[len(events)-1:]
+
+			if tt.streamed == "" {
+				s.NotContains(events[0].Tags, "resp.streamed")
+				s.Contains(events[0].Tags["resp.body"], `{"key":"value"}`)
+
+				return
+			}
+
+			s.Equal(tt.streamed, events[0].Tags["resp.streamed"])
+			s.NotContains(events[0].Tags, "resp.body")
+			s.NotContains(events[0].Tags, "resp.body.capture")
+			s.Equal(tt.sent, events[0].Extra["resp.bytes_sent"])
+
+			for name, value := range tt.tags {
+				s.Equal(value, events[0].Tags[name])
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package echosentrymiddleware
+
+import (
+	"math/rand/v2"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// TenantFn returns the tenant of the request, e.g. read from a header or set by auth middleware
+type TenantFn func(c echo.Context) string
+
+// TenantFromHeader reads the tenant from the header
+func TenantFromHeader(header string) TenantFn {
+	return func(c echo.Context) string {
+		return c.Request().Header.Get(header)
+	}
+}
+
+// TenantSampling defines sample rates of transactions per tenant, requires TenantFn
+type TenantSampling struct {
+	// Rates maps tenants to sample rates between 0 and 1
+	Rates map[string]float64
+	// Default is the sample rate of tenants missing in Rates and requests without a tenant
+	Default float64
+}
+
+func (s *TenantSampling) rate(tenant string) float64 {
+	if rate, ok := s.Rates[tenant]; ok {
+		return rate
+	}
+
+	return s.Default
+}
+
+func getTenant(c echo.Context, config SentryConfig) string {
+	if config.TenantFn == nil {
+		return ""
+	}
+
+	return config.TenantFn(c)
+}
+
+// tenantSampleOption samples the transaction with the rate of the tenant,
+// decisions of upstream services are kept unless ParentSamplingIgnore is set
+func tenantSampleOption(config SentryConfig, tenant string) sentry.SpanOption {
+	return func(span *sentry.Span) {
+		if config.TenantSampling == nil || span.Sampled != sentry.SampledUndefined {
+			return
+		}
+
+		if rand.Float64() < config.TenantSampling.rate(tenant) {
+			span.Sampled = sentry.SampledTrue
+		} else {
+			span.Sampled = sentry.SampledFalse
+		}
+	}
+}
+
+// dumpTenant tags the tenant on the span and the request scope, so error events carry it too
+func dumpTenant(span *sentry.Span, config SentryConfig, hub *sentry.Hub, tenant string) {
+	if tenant == "" {
+		return
+	}
+
+	setTag(span, config, "tenant", tenant)
+
+	if hub != nil {
+		hub.Scope().SetTag("tenant", config.Sanitizer.TagValue(tenant))
+	}
+}
@@ -0,0 +1,56 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestTenantSampling() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		CaptureErrors: true,
+		TenantFn:      TenantFromHeader("X-Tenant"),
+		TenantSampling: &TenantSampling{
+			Rates:   map[string]float64{"premium": 1},
+			Default: 0,
+		},
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return errors.New("test error")
+	})
+
+	tests := []struct {
+		tenant  string
+		trace   string
+		sampled sentry.Sampled
+	}{
+		{tenant: "premium", sampled: sentry.SampledTrue},
+		{tenant: "free", sampled: sentry.SampledFalse},
+		{tenant: "", sampled: sentry.SampledFalse},
+		{tenant: "free", trace: sampledTrace, sampled: sentry.SampledTrue},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant", tt.tenant)
+
+		if tt.trace != "" {
+			req.Header.Set(sentry.SentryTraceHeader, tt.trace)
+		}
+
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+		s.Equal(tt.sampled, span.Sampled, tt.tenant)
+		s.Equal(tt.tenant, span.Tags["tenant"])
+	}
+
+	events := s.errorEvents()
+	s.Require().Len(events, len(tests))
+	s.Equal("premium", events[0].Tags["tenant"])
+}
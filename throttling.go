@@ -0,0 +1,63 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const rateLimitInfoKey = "sentry.ratelimit"
+
+// RateLimitInfo describes the rate limiter which throttled a request
+type RateLimitInfo struct {
+	Identifier string
+	Rate       float64
+	Burst      int
+}
+
+// SetRateLimitInfo stores rate limiter details in echo context, they are tagged on 429 responses
+func SetRateLimitInfo(c echo.Context, info RateLimitInfo) {
+	c.Set(rateLimitInfoKey, info)
+}
+
+// WrapRateLimiterConfig wraps DenyHandler of echo's RateLimiter config, so the denied identifier
+// is recorded together with the given rate and burst of the store
+func WrapRateLimiterConfig(config middleware.RateLimiterConfig, rate float64, burst int) middleware.RateLimiterConfig {
+	denyHandler := config.DenyHandler
+	if denyHandler == nil {
+		denyHandler = middleware.DefaultRateLimiterConfig.DenyHandler
+	}
+
+	config.DenyHandler = func(c echo.Context, identifier string, err error) error {
+		SetRateLimitInfo(c, RateLimitInfo{
+			Identifier: identifier,
+			Rate:       rate,
+			Burst:      burst,
+		})
+
+		return denyHandler(c, identifier, err)
+	}
+
+	return config
+}
+
+// dumpRateLimit distinguishes throttled requests from genuine errors
+func dumpRateLimit(c echo.Context, config SentryConfig, span *sentry.Span, status int) {
+	if status != http.StatusTooManyRequests {
+		return
+	}
+
+	setTag(span, config, "ratelimit.throttled", "true")
+
+	info, ok := c.Get(rateLimitInfoKey).(RateLimitInfo)
+	if !ok {
+		return
+	}
+
+	setTag(span, config, "ratelimit.identifier", info.Identifier)
+	setTag(span, config, "ratelimit.burst", strconv.Itoa(info.Burst))
+	span.SetData("ratelimit.rate", info.Rate)
+}
@@ -0,0 +1,45 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+func (s *MiddlewareTestSuite) TestRateLimiter() {
+	var span *sentry.Span
+
+	s.e.Use(Middleware())
+	s.e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			span = sentry.TransactionFromContext(c.Request().Context())
+			return next(c)
+		}
+	})
+	s.e.Use(middleware.RateLimiterWithConfig(WrapRateLimiterConfig(middleware.RateLimiterConfig{
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:  rate.Limit(0.001),
+			Burst: 1,
+		}),
+	}, 0.001, 1)))
+	s.e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, status := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "1.2.3.4:1234"
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		s.Equal(status, rec.Code)
+	}
+
+	s.Equal("true", span.Tags["ratelimit.throttled"])
+	s.Equal("1.2.3.4", span.Tags["ratelimit.identifier"])
+	s.Equal("1", span.Tags["ratelimit.burst"])
+	s.Equal(0.001, span.Data["ratelimit.rate"])
+}
@@ -0,0 +1,75 @@
+package echosentrymiddleware
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+var _ wrappingWriter = (*timeoutDetector)(nil)
+
+// timeoutDetector detects requests abandoned by echo's Timeout middleware. On timeout http.TimeoutHandler writes
+// 503 directly to the writer, bypassing echo.Response, while the handler may still run in the background,
+// so the context must not be inspected. Headers written via echo.Response are recorded by a Before hook instead.
+type timeoutDetector struct {
+	http.ResponseWriter
+	echoStatus atomic.Int64
+	timedOut   bool
+}
+
+func newTimeoutDetector(c echo.Context) *timeoutDetector {
+	d := &timeoutDetector{ResponseWriter: c.Response().Writer}
+
+	resp := c.Response()
+	resp.Before(func() {
+		d.echoStatus.Store(int64(resp.Status))
+	})
+
+	return d
+}
+
+func (d *timeoutDetector) WriteHeader(code int) {
+	if code == http.StatusServiceUnavailable && d.echoStatus.Load() != http.StatusServiceUnavailable {
+		d.timedOut = true
+	}
+
+	d.ResponseWriter.WriteHeader(code)
+}
+
+func (d *timeoutDetector) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := d.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+
+	return pusher.Push(target, opts)
+}
+
+func (d *timeoutDetector) ReadFrom(r io.Reader) (int64, error) {
+	readerFrom, ok := d.ResponseWriter.(io.ReaderFrom)
+	if !ok {
+		return io.Copy(writerOnly{d}, r)
+	}
+
+	return readerFrom.ReadFrom(r)
+}
+
+// Unwrap returns the original writer, used by http.ResponseController
+func (d *timeoutDetector) Unwrap() http.ResponseWriter {
+	return d.ResponseWriter
+}
+
+// dumpTimeout records the response of a timed out request, the abandoned writer is never read
+func dumpTimeout(config SentryConfig, span *sentry.Span, request *http.Request) int {
+	setTag(span, config, "timeout", "true")
+	setTag(span, config, "request_id", request.Header.Get(echo.HeaderXRequestID))
+	setTag(span, config, "resp.status", strconv.Itoa(http.StatusServiceUnavailable))
+
+	span.Status = sentry.SpanStatusDeadlineExceeded
+
+	return http.StatusServiceUnavailable
+}
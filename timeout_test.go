@@ -0,0 +1,123 @@
+package echosentrymiddleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func (s *MiddlewareTestSuite) TestTimeoutMiddleware() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		AreHeadersDump: true,
+		IsBodyDump:     true,
+	}))
+	s.e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
+		Timeout:      time.Millisecond,
+		ErrorMessage: "timeout",
+	}))
+
+	var span *sentry.Span
+	done := make(chan struct{})
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		<-c.Request().Context().Done()
+		close(done)
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	<-done
+
+	s.Equal(http.StatusServiceUnavailable, rec.Code)
+	s.Equal("true", span.Tags["timeout"])
+	s.Equal("503", span.Tags["resp.status"])
+	s.Equal(sentry.SpanStatusDeadlineExceeded, span.Status)
+	s.Empty(span.Tags["resp.body"])
+}
+
+// TestTimeoutMiddlewareAbandonedContext fails with -race when the context is used after the timeout
+func (s *MiddlewareTestSuite) TestTimeoutMiddlewareAbandonedContext() {
+	mirror := &spanMirrorRecorder{}
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		SpanMirror:           mirror,
+		CaptureEventOnStatus: []StatusRange{{From: http.StatusInternalServerError, To: http.StatusNetworkAuthenticationRequired}},
+		ContextTags:          map[string]string{"user": "user"},
+		SecurityEvents:       NewSecurityEvents(time.Minute, 1),
+		AttachmentsFn: func(c echo.Context) []*sentry.Attachment {
+			_ = c.Request()
+			return nil
+		},
+	}))
+	s.e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{Timeout: time.Millisecond}))
+
+	done := make(chan struct{})
+	s.e.GET("/", func(c echo.Context) error {
+		defer close(done)
+
+		<-c.Request().Context().Done()
+		// give the middleware time to finish the request while the handler still runs
+		time.Sleep(10 * time.Millisecond)
+
+		c.Set("user", "late")
+		c.SetRequest(c.Request().WithContext(context.Background()))
+
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	<-done
+
+	s.Equal(http.StatusServiceUnavailable, rec.Code)
+	s.Equal(http.StatusServiceUnavailable, mirror.status)
+	s.Empty(s.errorEvents())
+}
+
+func (s *MiddlewareTestSuite) TestTimeoutMiddlewareNotTimedOut() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{}))
+	s.e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{Timeout: time.Minute}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Equal(http.StatusOK, rec.Code)
+	s.Empty(span.Tags["timeout"])
+	s.Equal("200", span.Tags["resp.status"])
+}
+
+func (s *MiddlewareTestSuite) TestTimeoutDetectorPassThrough() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{}))
+	s.e.GET("/", func(c echo.Context) error {
+		pusher, ok := c.Response().Writer.(http.Pusher)
+		s.Require().True(ok)
+		s.Require().NoError(pusher.Push("/style.css", nil))
+
+		readerFrom, ok := c.Response().Writer.(io.ReaderFrom)
+		s.Require().True(ok)
+		_, err := readerFrom.ReadFrom(strings.NewReader("body"))
+
+		return err
+	})
+
+	w := &fullWriterMock{ResponseRecorder: httptest.NewRecorder()}
+	s.e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Equal("/style.css", w.pushed)
+	s.True(w.readFrom)
+	s.Equal("body", w.Body.String())
+}
@@ -0,0 +1,24 @@
+package echosentrymiddleware
+
+import (
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const (
+	// handlerDurationKey is the data key of the time spent in the handler chain
+	handlerDurationKey = "http.handler_ms"
+	// totalDurationKey is the data key of the whole request, including writing the response and dumping
+	totalDurationKey = "http.total_ms"
+)
+
+// recordHandlerDuration records the time spent in the handler chain since start
+func recordHandlerDuration(span *sentry.Span, config SentryConfig, start time.Time) {
+	span.SetData(handlerDurationKey, config.Clock().Sub(start).Milliseconds())
+}
+
+// recordTotalDuration records the duration of the finished span
+func recordTotalDuration(span *sentry.Span) {
+	span.SetData(totalDurationKey, span.EndTime.Sub(span.StartTime).Milliseconds())
+}
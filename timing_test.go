@@ -0,0 +1,83 @@
+package echosentrymiddleware
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestDurations() {
+	now := time.Now()
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		FinishAfterResponse: true,
+		Clock: func() time.Time {
+			return now
+		},
+	}))
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		now = now.Add(10 * time.Millisecond)
+		return c.String(http.StatusOK, "test")
+	})
+
+	rec := &slowFlushRecorder{
+		ResponseRecorder: httptest.NewRecorder(),
+		flush: func() {
+			now = now.Add(30 * time.Millisecond)
+		},
+	}
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Equal(int64(10), span.Data[handlerDurationKey])
+	s.Equal(int64(40), span.Data[totalDurationKey])
+}
+
+func (s *MiddlewareTestSuite) TestClockChildSpans() {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.e.Renderer = WrapRenderer(&templateRenderer{
+		templates: template.Must(template.New("hello").Parse("Hello, {{.}}!")),
+	})
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		IsBodyDump:      true,
+		InstrumentDumps: true,
+		Clock: func() time.Time {
+			return now
+		},
+	}))
+	s.e.Use(WrapMiddleware("auth", func(next echo.HandlerFunc) echo.HandlerFunc {
+		return next
+	}))
+	s.e.POST("/", func(c echo.Context) error {
+		now = now.Add(10 * time.Millisecond)
+		return c.Render(http.StatusOK, "hello", "World")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := s.transport.Events()
+	s.Require().Len(events, 1)
+	s.Equal(now.Add(-10*time.Millisecond), events[0].StartTime)
+	s.Equal(now, events[0].Timestamp)
+
+	ops := make(map[string]bool)
+
+	for _, span := range events[0].Spans {
+		ops[span.Op] = true
+
+		s.False(span.StartTime.Before(events[0].StartTime), span.Op)
+		s.False(span.EndTime.After(events[0].Timestamp), span.Op)
+	}
+
+	s.Equal(map[string]bool{"http.handler": true, "middleware": true, "middleware.dump": true, "template.render": true}, ops)
+}
@@ -0,0 +1,22 @@
+package echosentrymiddleware
+
+import (
+	"crypto/tls"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// dumpTLS records negotiated TLS parameters of the connection
+func dumpTLS(span *sentry.Span, state *tls.ConnectionState) {
+	if state == nil {
+		return
+	}
+
+	span.SetData("tls.version", tls.VersionName(state.Version))
+	span.SetData("tls.cipher", tls.CipherSuiteName(state.CipherSuite))
+	span.SetData("tls.client_cert", len(state.PeerCertificates) > 0)
+
+	if state.ServerName != "" {
+		span.SetData("tls.server_name", state.ServerName)
+	}
+}
@@ -0,0 +1,31 @@
+package echosentrymiddleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestTLS() {
+	s.e.Use(Middleware())
+
+	var span *sentry.Span
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+	req.TLS.Version = tls.VersionTLS10
+	req.TLS.CipherSuite = tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.NotNil(span)
+	s.Equal("TLS 1.0", span.Data["tls.version"])
+	s.Equal("TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA", span.Data["tls.cipher"])
+	s.Equal("example.com", span.Data["tls.server_name"])
+	s.Equal(false, span.Data["tls.client_cert"])
+}
@@ -0,0 +1,156 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// uploadTracker watches the multipart boundary in the request body as it is read
+// and records a child span per part, from the read of its delimiter until the next one
+type uploadTracker struct {
+	mu     sync.Mutex
+	config SentryConfig
+	parent *sentry.Span
+	delim  []byte
+	buf    []byte
+	carry  []byte
+	// afterDelim is set when the byte telling a part from the closing delimiter is not read yet
+	afterDelim bool
+	closed     bool
+	part       *sentry.Span
+	parts      int
+	bytes      int64
+}
+
+// uploadReader passes reads of the body to the tracker
+type uploadReader struct {
+	io.ReadCloser
+	tracker *uploadTracker
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.tracker.read(p[:n], err)
+
+	return n, err
+}
+
+// traceUpload wraps the body of large multipart requests, it returns nil for other requests
+func traceUpload(config SentryConfig, span *sentry.Span, request *http.Request) *uploadTracker {
+	if config.UploadSpansMinSize <= 0 || request.Body == nil || request.Body == http.NoBody {
+		return nil
+	}
+
+	if request.ContentLength >= 0 && request.ContentLength < config.UploadSpansMinSize {
+		return nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(request.Header.Get(echo.HeaderContentType))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil
+	}
+
+	tracker := &uploadTracker{
+		config: config,
+		parent: span,
+		delim:  []byte("--" + params["boundary"]),
+	}
+	request.Body = &uploadReader{ReadCloser: request.Body, tracker: tracker}
+
+	return tracker
+}
+
+func (t *uploadTracker) read(p []byte, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.bytes += int64(len(p))
+
+	if !t.closed {
+		t.scan(p)
+	}
+
+	if err != nil {
+		t.finishPart()
+	}
+}
+
+// scan looks for delimiters in p, including the ones split between reads
+func (t *uploadTracker) scan(p []byte) {
+	data := append(append(t.buf[:0], t.carry...), p...)
+	t.buf = data
+
+	for len(data) > 0 {
+		if t.afterDelim {
+			t.afterDelim = false
+
+			// the closing delimiter is followed by "--"
+			if data[0] == '-' {
+				t.closed = true
+				t.carry = t.carry[:0]
+
+				return
+			}
+
+			t.startPart()
+		}
+
+		i := bytes.Index(data, t.delim)
+		if i < 0 {
+			break
+		}
+
+		t.finishPart()
+		t.afterDelim = true
+		data = data[i+len(t.delim):]
+	}
+
+	t.carry = append(t.carry[:0], data[max(0, len(data)-len(t.delim)+1):]...)
+}
+
+func (t *uploadTracker) startPart() {
+	t.parts++
+
+	// the span gets its own hub, so it doesn't push to the scopes of the handler
+	hub := sentry.GetHubFromContext(t.parent.Context())
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+
+	ctx := sentry.SetHubOnContext(t.parent.Context(), hub.Clone())
+	t.part = sentry.StartSpan(ctx, "http.upload",
+		sentry.WithDescription("part "+strconv.Itoa(t.parts)), idOption(t.config.IDGenerator))
+	t.part.StartTime = t.config.Clock()
+	t.part.SetData("http.upload.part", t.parts)
+}
+
+func (t *uploadTracker) finishPart() {
+	if t.part == nil {
+		return
+	}
+
+	finishWithClock(t.part, t.config.Clock)()
+	t.part = nil
+}
+
+// finish ends the part left open by a handler not reading the whole body and records the totals
+func (t *uploadTracker) finish() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.finishPart()
+	t.parent.SetData("http.upload.bytes", t.bytes)
+	t.parent.SetData("http.upload.parts", t.parts)
+}
@@ -0,0 +1,109 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing/iotest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func multipartBody(s *MiddlewareTestSuite) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	s.Require().NoError(writer.WriteField("name", "report"))
+
+	file, err := writer.CreateFormFile("file", "report.txt")
+	s.Require().NoError(err)
+	_, err = file.Write([]byte(strings.Repeat("data", 1000)))
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func (s *MiddlewareTestSuite) uploadSpans() []*sentry.Span {
+	events := s.transport.Events()
+	s.Require().Len(events, 1)
+
+	var spans []*sentry.Span
+
+	for _, span := range events[0].Spans {
+		if span.Op == "http.upload" {
+			spans = append(spans, span)
+		}
+	}
+
+	return spans
+}
+
+func (s *MiddlewareTestSuite) TestUploadSpans() {
+	tests := []struct {
+		name string
+		wrap func(io.Reader) io.Reader
+	}{
+		{name: "whole reads", wrap: func(r io.Reader) io.Reader { return r }},
+		{name: "one byte reads", wrap: iotest.OneByteReader},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			s.e.Use(MiddlewareWithConfig(SentryConfig{UploadSpansMinSize: 1}))
+			s.e.POST("/upload", func(c echo.Context) error {
+				// the handler's own spans keep working while the parts are traced
+				span := sentry.StartSpan(c.Request().Context(), "handler.work")
+				defer span.Finish()
+
+				form, err := c.MultipartForm()
+				if err != nil {
+					return err
+				}
+
+				return c.String(http.StatusOK, form.Value["name"][0])
+			})
+
+			body, contentType := multipartBody(s)
+			size := body.Len()
+			req := httptest.NewRequest(http.MethodPost, "/upload", tt.wrap(body))
+			req.Header.Set(echo.HeaderContentType, contentType)
+			req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+			rec := httptest.NewRecorder()
+			s.e.ServeHTTP(rec, req)
+
+			s.Equal(http.StatusOK, rec.Code)
+			s.Equal("report", rec.Body.String())
+
+			spans := s.uploadSpans()
+			s.Require().Len(spans, 2)
+			s.Equal("part 1", spans[0].Description)
+			s.Equal("part 2", spans[1].Description)
+			s.False(spans[1].StartTime.Before(spans[0].EndTime))
+
+			tx := s.transport.Events()[0]
+			s.Equal(int64(size), tx.Extra["http.upload.bytes"])
+			s.Equal(2, tx.Extra["http.upload.parts"])
+		})
+	}
+}
+
+func (s *MiddlewareTestSuite) TestUploadSpansSkipped() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{UploadSpansMinSize: 1 << 20}))
+	s.e.POST("/upload", func(c echo.Context) error {
+		_, err := c.MultipartForm()
+		return err
+	})
+
+	body, contentType := multipartBody(s)
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Empty(s.uploadSpans())
+}
@@ -0,0 +1,107 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// URLScrubber rewrites the request URI before it is used in the request_uri tag, the transaction name
+// and the query string of events
+type URLScrubber func(uri string) string
+
+// DefaultURLScrubberMaxDigits is the longest number kept by the default URL scrubber
+const DefaultURLScrubberMaxDigits = 9
+
+// minTokenLength is the length of strings with digits considered to be tokens
+const minTokenLength = 32
+
+var (
+	jwtPattern   = `[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`
+	tokenPattern = `[A-Za-z0-9_-]{` + strconv.Itoa(minTokenLength) + `,}`
+)
+
+// NewURLScrubber returns a scrubber masking values of sensitive query parameters, JWTs and other long tokens,
+// emails and numbers longer than maxDigits
+func NewURLScrubber(sensitiveFields []string, maxDigits int) URLScrubber {
+	re := regexp.MustCompile(jwtPattern + `|` + emailPattern + `|` + tokenPattern +
+		`|\d{` + strconv.Itoa(maxDigits+1) + `,}`)
+
+	return func(uri string) string {
+		path, query, hasQuery := strings.Cut(uri, "?")
+		if hasQuery {
+			path += "?" + scrubQuery(query, sensitiveFields)
+		}
+
+		if !re.MatchString(path) {
+			return path
+		}
+
+		return re.ReplaceAllStringFunc(path, func(match string) string {
+			// long words without digits are usually slugs
+			if len(match) >= minTokenLength && !strings.ContainsAny(match, ".@%") && !strings.ContainsFunc(match, unicode.IsDigit) {
+				return match
+			}
+
+			return scrubbedValue
+		})
+	}
+}
+
+// scrubQuery masks values of sensitive query parameters keeping the rest of the query as is
+func scrubQuery(query string, sensitiveFields []string) string {
+	params := strings.Split(query, "&")
+
+	for i, param := range params {
+		key, _, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+
+		if name, err := url.QueryUnescape(key); err == nil && isSensitive(name, sensitiveFields) {
+			params[i] = key + "=" + scrubbedValue
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// dumpQuery records query parameters as "http.query" data, values are masked when sensitive or scrubbed otherwise
+func dumpQuery(span *sentry.Span, config SentryConfig, request *http.Request) {
+	query := request.URL.Query()
+	if len(query) == 0 {
+		return
+	}
+
+	for name, values := range query {
+		for i := range values {
+			if isSensitive(name, config.SensitiveFields) {
+				values[i] = scrubbedValue
+				auditRedaction(span, "http.query", name)
+			} else if scrubbed := config.URLScrubber(values[i]); scrubbed != values[i] {
+				values[i] = scrubbed
+				auditRedaction(span, "http.query", name)
+			}
+
+			values[i] = escapeInvalidUTF8(values[i])
+		}
+	}
+
+	span.SetData("http.query", map[string][]string(query))
+}
+
+// scrubEventQuery scrubs the query string in the request interface of the events sent via the hub
+func scrubEventQuery(hub *sentry.Hub, scrubber URLScrubber) {
+	hub.Scope().AddEventProcessor(func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		if event.Request != nil && event.Request.QueryString != "" {
+			event.Request.QueryString = strings.TrimPrefix(scrubber("?"+event.Request.QueryString), "?")
+		}
+
+		return event
+	})
+}
@@ -0,0 +1,86 @@
+package echosentrymiddleware
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+const modulePath = "github.com/adlandh/echo-sentry-middleware"
+
+// getVersionTags returns versions of echo, this middleware and the Go runtime, resolved once
+var getVersionTags = sync.OnceValue(func() map[string]string {
+	tags := map[string]string{
+		"echo.version":       echo.Version,
+		"go.version":         runtime.Version(),
+		"middleware.version": "unknown",
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return tags
+	}
+
+	if info.Main.Path == modulePath {
+		tags["middleware.version"] = info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			tags["middleware.version"] = dep.Version
+		}
+	}
+
+	return tags
+})
+
+func dumpVersions(span *sentry.Span, config SentryConfig) {
+	for tag, value := range getVersionTags() {
+		setTag(span, config, tag, value)
+	}
+}
+
+// getBuildInfo returns the VCS revision, commit time and main module version of the binary, resolved once
+var getBuildInfo = sync.OnceValue(func() map[string]string {
+	return buildInfoTags(debug.ReadBuildInfo())
+})
+
+func buildInfoTags(info *debug.BuildInfo, ok bool) map[string]string {
+	tags := make(map[string]string)
+	if !ok {
+		return tags
+	}
+
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		tags["app.version"] = info.Main.Version
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			tags[setting.Key] = setting.Value
+		}
+	}
+
+	return tags
+}
+
+// dumpBuildInfo tags the build info and adds it as the "build" context of the transaction
+func dumpBuildInfo(span *sentry.Span, config SentryConfig) {
+	tags := getBuildInfo()
+	if len(tags) == 0 {
+		return
+	}
+
+	build := make(sentry.Context, len(tags))
+
+	for tag, value := range tags {
+		setTag(span, config, tag, value)
+		build[tag] = value
+	}
+
+	span.SetContext("build", build)
+}
@@ -0,0 +1,76 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *MiddlewareTestSuite) TestVersionTags() {
+	var span *sentry.Span
+
+	s.e.Use(MiddlewareWithConfig(SentryConfig{
+		AddVersionTags: true,
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		span = sentry.TransactionFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.NotNil(span)
+	s.Equal(echo.Version, span.Tags["echo.version"])
+	s.Equal(runtime.Version(), span.Tags["go.version"])
+	s.NotEmpty(span.Tags["middleware.version"])
+}
+
+func TestBuildInfoTags(t *testing.T) {
+	require.Empty(t, buildInfoTags(nil, false))
+
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "0123456789abcdef"},
+			{Key: "vcs.time", Value: "2024-01-02T03:04:05Z"},
+			{Key: "vcs.modified", Value: "false"},
+			{Key: "GOOS", Value: "linux"},
+		},
+	}
+
+	require.Equal(t, map[string]string{
+		"app.version":  "v1.2.3",
+		"vcs.revision": "0123456789abcdef",
+		"vcs.time":     "2024-01-02T03:04:05Z",
+		"vcs.modified": "false",
+	}, buildInfoTags(info, true))
+
+	info.Main.Version = "(devel)"
+	require.NotContains(t, buildInfoTags(info, true), "app.version")
+}
+
+func (s *MiddlewareTestSuite) TestBuildInfo() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{AddBuildInfo: true}))
+	s.e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Require().Len(s.transport.Events(), 1)
+
+	event := s.transport.Events()[0]
+	for tag, value := range getBuildInfo() {
+		s.Equal(value, event.Tags[tag])
+		s.Equal(value, event.Contexts["build"][tag])
+	}
+}
@@ -0,0 +1,76 @@
+package echosentrymiddleware
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+const webhookSignatureKey = "sentry.webhook_signature"
+
+// ErrInvalidWebhookSignature is captured for invalid webhook signatures when CaptureInvalidWebhookSignatures is set
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// WebhookSignature is the outcome of the signature verification of a webhook
+type WebhookSignature struct {
+	// Provider sending the webhook, e.g. "stripe" or "github"
+	Provider string
+	// KeyID identifies the secret the signature was checked with, e.g. its version, never the secret itself
+	KeyID string
+	Valid bool
+}
+
+// SetWebhookSignature stores the outcome of the signature verification in echo context and tags the events
+// captured during the rest of the request with it, the transaction is tagged after the handler
+func SetWebhookSignature(c echo.Context, signature WebhookSignature) {
+	c.Set(webhookSignatureKey, signature)
+
+	hub := sentry.GetHubFromContext(c.Request().Context())
+	if hub == nil {
+		return
+	}
+
+	for tag, value := range webhookTags(signature) {
+		if value != "" {
+			hub.Scope().SetTag(tag, value)
+		}
+	}
+}
+
+func webhookTags(signature WebhookSignature) map[string]string {
+	result := "invalid"
+	if signature.Valid {
+		result = "valid"
+	}
+
+	return map[string]string{
+		"webhook.provider":  signature.Provider,
+		"webhook.key_id":    signature.KeyID,
+		"webhook.signature": result,
+	}
+}
+
+// dumpWebhookSignature tags the verification outcome and captures invalid signatures as security events
+func dumpWebhookSignature(c echo.Context, config SentryConfig, span *sentry.Span) {
+	signature, ok := c.Get(webhookSignatureKey).(WebhookSignature)
+	if !ok {
+		return
+	}
+
+	hub := sentry.GetHubFromContext(span.Context())
+
+	for tag, value := range webhookTags(signature) {
+		setTag(span, config, tag, value)
+
+		// the scope of the handler is gone, events captured after it carry the outcome as well
+		if hub != nil && value != "" {
+			hub.Scope().SetTag(config.Sanitizer.TagName(tag), config.Sanitizer.TagValue(value))
+		}
+	}
+
+	if !signature.Valid && config.CaptureInvalidWebhookSignatures {
+		captureError(c, config, span, fmt.Errorf("%w: provider %q", ErrInvalidWebhookSignature, signature.Provider))
+	}
+}
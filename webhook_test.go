@@ -0,0 +1,58 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestWebhookSignature() {
+	s.e.Use(MiddlewareWithConfig(SentryConfig{CaptureInvalidWebhookSignatures: true}))
+	s.e.POST("/webhooks/stripe", func(c echo.Context) error {
+		valid := c.Request().Header.Get("Stripe-Signature") == "good"
+		SetWebhookSignature(c, WebhookSignature{Provider: "stripe", KeyID: "whsec_v2", Valid: valid})
+
+		if !valid {
+			CaptureMessage(c, "rejected webhook")
+
+			return c.NoContent(http.StatusUnauthorized)
+		}
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, signature := range []string{"good", "forged"} {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/stripe", nil)
+		req.Header.Set("Stripe-Signature", signature)
+		req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+		s.e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var transactions []*sentry.Event
+
+	for _, event := range s.transport.Events() {
+		if event.Type == "transaction" {
+			transactions = append(transactions, event)
+		}
+	}
+
+	s.Require().Len(transactions, 2)
+	s.Equal("stripe", transactions[0].Tags["webhook.provider"])
+	s.Equal("whsec_v2", transactions[0].Tags["webhook.key_id"])
+	s.Equal("valid", transactions[0].Tags["webhook.signature"])
+	s.Equal("invalid", transactions[1].Tags["webhook.signature"])
+
+	events := s.errorEvents()
+	s.Require().Len(events, 2)
+
+	// captured by the handler right after the verification
+	s.Equal("rejected webhook", events[0].Message)
+	s.Equal("stripe", events[0].Tags["webhook.provider"])
+	s.Equal("invalid", events[0].Tags["webhook.signature"])
+
+	s.Contains(events[1].Exception[len(events[1].Exception)-1].Value, ErrInvalidWebhookSignature.Error())
+	s.Equal("stripe", events[1].Tags["webhook.provider"])
+	s.Equal("invalid", events[1].Tags["webhook.signature"])
+}
@@ -0,0 +1,43 @@
+package echosentrymiddleware
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+var wrappedMiddlewareCount atomic.Uint64
+
+// WrapMiddleware wraps an echo middleware into a child span named after it.
+// The span covers the time spent in the middleware until it calls the next handler,
+// so wrapping the whole chain gives a waterfall of the middleware inside of the transaction.
+// The middleware should be registered after the Sentry middleware.
+func WrapMiddleware(name string, mw echo.MiddlewareFunc) echo.MiddlewareFunc {
+	// key of the span in echo context, unique for each wrapped middleware
+	key := "sentry.middleware." + name + "." + strconv.FormatUint(wrappedMiddlewareCount.Add(1), 10)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		h := mw(func(c echo.Context) error {
+			if finish, ok := c.Get(key).(func()); ok {
+				finish()
+			}
+
+			return next(c)
+		})
+
+		return func(c echo.Context) error {
+			if sentry.SpanFromContext(c.Request().Context()) == nil {
+				return h(c)
+			}
+
+			_, finish := startHandledSpan(c.Request().Context(), "middleware", name)
+			defer finish()
+
+			c.Set(key, finish)
+
+			return h(c)
+		}
+	}
+}
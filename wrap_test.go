@@ -0,0 +1,49 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *MiddlewareTestSuite) TestWrapMiddleware() {
+	s.e.Use(Middleware())
+	s.e.Use(WrapMiddleware("auth", func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			time.Sleep(time.Millisecond)
+			return next(c)
+		}
+	}))
+	s.e.Use(WrapMiddleware("deny", func(echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return c.NoContent(http.StatusForbidden)
+		}
+	}))
+	s.e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sentry.SentryTraceHeader, sampledTrace)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	s.Equal(http.StatusForbidden, rec.Code)
+
+	events := s.transport.Events()
+	s.Len(events, 1)
+
+	spans := make(map[string]*sentry.Span)
+	for _, span := range events[0].Spans {
+		spans[span.Description] = span
+	}
+
+	s.Len(spans, 3) // handler span, auth and deny
+	s.Equal("middleware", spans["auth"].Op)
+	s.Equal("middleware", spans["deny"].Op)
+	s.Equal(spans["/"].SpanID, spans["auth"].ParentSpanID)
+	s.Equal(spans["/"].SpanID, spans["deny"].ParentSpanID)
+	s.False(spans["auth"].EndTime.After(spans["deny"].StartTime))
+}
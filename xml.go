@@ -0,0 +1,133 @@
+package echosentrymiddleware
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/labstack/echo/v4"
+)
+
+// maxXMLSnippetSize is the size limit of the pretty-printed XML snippet
+const maxXMLSnippetSize = 2048
+
+const headerSOAPAction = "SOAPAction"
+
+func isXMLMediaType(mediaType string) bool {
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// getXMLAction returns SOAP action from SOAPAction header (SOAP 1.1) or content type (SOAP 1.2)
+func getXMLAction(header http.Header) string {
+	if action := strings.Trim(header.Get(headerSOAPAction), `"`); action != "" {
+		return action
+	}
+
+	_, params, err := mime.ParseMediaType(header.Get(echo.HeaderContentType))
+	if err != nil {
+		return ""
+	}
+
+	return params["action"]
+}
+
+// dumpXMLBody records root element, action and a scrubbed pretty-printed snippet of the XML body
+func dumpXMLBody(span *sentry.Span, config SentryConfig, prefix string, header http.Header, body []byte) {
+	root, snippet, err := summarizeXML(body, config.SensitiveFields, func(name string) {
+		auditRedaction(span, prefix+".xml", name)
+	})
+	if err != nil {
+		setTag(span, config, prefix+".body", detectPII(span, config, prefix+".body", string(body)))
+		return
+	}
+
+	setTag(span, config, prefix+".xml.root", root)
+	setTag(span, config, prefix+".xml.action", getXMLAction(header))
+	span.SetData(prefix+".xml.snippet", limitStringWithDots(detectPII(span, config, prefix+".xml", escapeInvalidUTF8(snippet)), maxXMLSnippetSize))
+}
+
+// summarizeXML returns the root element name and the indented document with sensitive elements
+// and attributes scrubbed, names of scrubbed ones are passed to onRedact
+func summarizeXML(body []byte, sensitiveFields []string, onRedact func(name string)) (string, string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	buf := new(bytes.Buffer)
+	encoder := xml.NewEncoder(buf)
+	encoder.Indent("", "  ")
+
+	var (
+		root string
+		// depth of the outermost sensitive element, 0 if not inside one
+		sensitiveDepth, depth int
+	)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return "", "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+
+			if root == "" {
+				root = t.Name.Local
+			}
+
+			if sensitiveDepth == 0 && isSensitive(t.Name.Local, sensitiveFields) {
+				sensitiveDepth = depth
+				onRedact(t.Name.Local)
+			}
+
+			for i := range t.Attr {
+				if isSensitive(t.Attr[i].Name.Local, sensitiveFields) {
+					t.Attr[i].Value = scrubbedValue
+					onRedact(t.Attr[i].Name.Local)
+				}
+			}
+
+			token = t
+		case xml.EndElement:
+			if sensitiveDepth == depth {
+				sensitiveDepth = 0
+			}
+
+			depth--
+		case xml.CharData:
+			if sensitiveDepth > 0 {
+				if len(bytes.TrimSpace(t)) == 0 {
+					continue
+				}
+
+				token = xml.CharData(scrubbedValue)
+			} else {
+				token = xml.CharData(bytes.TrimSpace(t))
+			}
+		case xml.Comment, xml.ProcInst, xml.Directive:
+			continue
+		}
+
+		if err := encoder.EncodeToken(token); err != nil {
+			return "", "", err
+		}
+	}
+
+	if root == "" {
+		return "", "", io.ErrUnexpectedEOF
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return "", "", err
+	}
+
+	return root, buf.String(), nil
+}
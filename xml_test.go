@@ -0,0 +1,24 @@
+package echosentrymiddleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeXML(t *testing.T) {
+	root, snippet, err := summarizeXML([]byte(`<a><b>1</b><secret><c>2</c></secret></a>`), DefaultSensitiveFields, func(string) {})
+	require.NoError(t, err)
+	require.Equal(t, "a", root)
+	require.Equal(t, "<a>\n  <b>1</b>\n  <secret>\n    <c>[scrubbed]</c>\n  </secret>\n</a>", snippet)
+
+	_, _, err = summarizeXML([]byte(`not xml`), DefaultSensitiveFields, func(string) {})
+	require.Error(t, err)
+}
+
+func TestGetXMLAction(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", `application/soap+xml; charset=utf-8; action="urn:Login"`)
+	require.Equal(t, "urn:Login", getXMLAction(header))
+}